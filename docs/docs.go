@@ -14,12 +14,106 @@ const docTemplate = `{
     },
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
+    "securityDefinitions": {
+        "Bearer": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header",
+            "description": "Токен из /auth/login в формате: Bearer <token>. Без токена маршруты /users, /tasks и /reports отвечают 401, при нехватке прав роли - 403. Администратор (admin) имеет доступ ко всему; руководитель (manager) - к пользователям своей команды, их задачам и трудозатратам; сотрудник (employee) - только к себе и своим задачам. Пока ни у одного пользователя нет пароля, вместо токена можно передать AUTH_BOOTSTRAP_SECRET с правами администратора: назначьте пользователю роль admin, затем задайте ему пароль."
+        },
+        "ApiKey": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header",
+            "description": "Ключ API для интеграций без входа пользователя. Выдается администратором через /apiKeys/create и проверяется только на маршрутах, разрешенных его областям: reports:read - /users/laborCost и /reports/laborCost, tasks:write - /tasks/create. Отозванный ключ отвечает 401."
+        }
+    },
+    "security": [
+        {
+            "Bearer": []
+        }
+    ],
     "paths": {
+        "/auth/login": {
+            "post": {
+                "summary": "Вход пользователя",
+                "description": "Проверяет пароль пользователя и выдает подписанный JWT для заголовка Authorization: Bearer.",
+                "operationId": "login",
+                "security": [],
+                "parameters": [
+                    {
+                        "name": "body",
+                        "in": "body",
+                        "description": "ID и пароль пользователя.",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/LoginInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный вход.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user_id": {
+                                    "type": "string",
+                                    "format": "uuid",
+                                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                                },
+                                "token": {
+                                    "type": "string",
+                                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                                },
+                                "expires_at": {
+                                    "type": "string",
+                                    "format": "date-time",
+                                    "example": "2024-07-08T12:00:00Z"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректное тело запроса.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось декодировать тело запроса: текст ошибки"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный ID пользователя или пароль.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Неверный ID пользователя или пароль"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
     "/tasks/create/{user_id}": {
             "post": {
                 "summary": "Создание нового задания",
-                "description": "Создает новое задание для пользователя с указанным ID.",
+                "description": "Создает новое задание для пользователя с указанным ID. Из тела учитываются только название и описание, задание создается в состоянии new.",
                 "operationId": "createTask",
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
                 "parameters": [
                     {
                         "name": "user_id",
@@ -47,7 +141,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Ошибка в запросе, например, неверный формат параметров или ошибка при декодировании данных.",
+                        "description": "Ошибка в запросе, например, неверный формат параметров или ошибка при декодировании данных, пустое или длиннее 255 символов название.",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -76,7 +170,7 @@ const docTemplate = `{
         "/tasks/start/{id}": {
             "post": {
                 "summary": "Старт таймера задачи",
-                "description": "Запускает таймер для задачи с указанным ID.",
+                "description": "Запускает таймер для задачи с указанным ID. У задачи один таймер: пока он запущен одним исполнителем, другие исполнители не могут вести по ней время одновременно.",
                 "operationId": "startTaskTimer",
                 "parameters": [
                     {
@@ -86,6 +180,14 @@ const docTemplate = `{
                         "required": true,
                         "type": "string",
                         "format": "uuid"
+                    },
+                    {
+                        "name": "user_id",
+                        "in": "query",
+                        "description": "ID исполнителя, на которого записывается сессия. По умолчанию - вызывающий пользователь. Указать другого исполнителя может администратор, а руководитель - только исполнителя из своей команды; иначе отвечается 403.",
+                        "required": false,
+                        "type": "string",
+                        "format": "uuid"
                     }
                 ],
                 "responses": {
@@ -95,6 +197,30 @@ const docTemplate = `{
                             "$ref": "#/definitions/Tasks"
                         }
                     },
+                    "400": {
+                        "description": "Некорректный ID задачи или не удалось определить исполнителя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "На задачу назначено несколько пользователей, укажите user_id"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Запуск таймера от имени другого пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Нельзя запускать таймер от имени другого пользователя"
+                                }
+                            }
+                        }
+                    },
                     "404": {
                         "description": "Задача не найдена.",
                         "schema": {
@@ -107,6 +233,12 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "409": {
+                        "description": "Недопустимый переход состояния задачи.",
+                        "schema": {
+                            "$ref": "#/definitions/StateConflict"
+                        }
+                    },
                     "500": {
                         "description": "Ошибка при обновлении задачи.",
                         "schema": {
@@ -144,6 +276,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/Tasks"
                         }
                     },
+                    "400": {
+                        "description": "Некорректный ID задачи.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Некорректный ID задачи: текст ошибки"
+                                }
+                            }
+                        }
+                    },
                     "404": {
                         "description": "Задача не найдена.",
                         "schema": {
@@ -156,6 +300,12 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "409": {
+                        "description": "Недопустимый переход состояния задачи.",
+                        "schema": {
+                            "$ref": "#/definitions/StateConflict"
+                        }
+                    },
                     "500": {
                         "description": "Ошибка при обновлении задачи.",
                         "schema": {
@@ -171,60 +321,66 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/create": {
+        "/tasks/pause/{id}": {
             "post": {
-                "summary": "Создание нового пользователя",
-                "description": "Создает нового пользователя с указанными данными.",
-                "operationId": "createUser",
+                "summary": "Пауза таймера задачи",
+                "description": "Приостанавливает таймер задачи с указанным ID. Отработанное время сохраняется в hours/minutes/seconds.",
+                "operationId": "pauseTaskTimer",
                 "parameters": [
                     {
-                        "in": "body",
-                        "name": "body",
-                        "description": "Данные нового пользователя.",
+                        "name": "id",
+                        "in": "path",
+                        "description": "ID задачи для паузы таймера.",
                         "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/Users"
-                        }
+                        "type": "string",
+                        "format": "uuid"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное создание пользователя.",
+                        "description": "Успешная пауза таймера задачи.",
+                        "schema": {
+                            "$ref": "#/definitions/Tasks"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID задачи.",
                         "schema": {
                             "type": "object",
                             "properties": {
-                                "user_id": {
-                                    "type": "string",
-                                    "format": "uuid",
-                                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                                },
-                                "msg": {
+                                "error": {
                                     "type": "string",
-                                    "example": "Создание пользователя прошло успешно"
+                                    "example": "Некорректный ID задачи: текст ошибки"
                                 }
                             }
                         }
                     },
-                    "400": {
-                        "description": "Ошибка в запросе, например, неверный формат параметров или ошибка при декодировании данных.",
+                    "404": {
+                        "description": "Задача не найдена.",
                         "schema": {
                             "type": "object",
                             "properties": {
                                 "error": {
                                     "type": "string",
-                                    "example": "Не удалось создать пользователя: текст ошибки"
+                                    "example": "Задача не найдена"
                                 }
                             }
                         }
                     },
+                    "409": {
+                        "description": "Недопустимый переход состояния задачи.",
+                        "schema": {
+                            "$ref": "#/definitions/StateConflict"
+                        }
+                    },
                     "500": {
-                        "description": "Внутренняя ошибка сервера, например, ошибка при создании пользователя.",
+                        "description": "Ошибка при обновлении задачи.",
                         "schema": {
                             "type": "object",
                             "properties": {
                                 "error": {
                                     "type": "string",
-                                    "example": "Не удалось создать пользователя: текст ошибки"
+                                    "example": "Ошибка при обновлении задачи: текст ошибки"
                                 }
                             }
                         }
@@ -232,59 +388,86 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/delete/{id}": {
-            "delete": {
-                "summary": "Удаление пользователя по ID",
-                "description": "Удаляет пользователя с указанным ID.",
-                "operationId": "deleteUserById",
+        "/tasks/resume/{id}": {
+            "post": {
+                "summary": "Возобновление таймера задачи",
+                "description": "Возобновляет таймер задачи, находящейся на паузе.",
+                "operationId": "resumeTaskTimer",
                 "parameters": [
                     {
                         "name": "id",
                         "in": "path",
-                        "description": "ID пользователя для удаления.",
+                        "description": "ID задачи для возобновления таймера.",
                         "required": true,
                         "type": "string",
                         "format": "uuid"
+                    },
+                    {
+                        "name": "user_id",
+                        "in": "query",
+                        "description": "ID исполнителя, на которого записывается сессия. По умолчанию - вызывающий пользователь. Указать другого исполнителя может администратор, а руководитель - только исполнителя из своей команды; иначе отвечается 403.",
+                        "required": false,
+                        "type": "string",
+                        "format": "uuid"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное удаление пользователя.",
+                        "description": "Успешное возобновление таймера задачи.",
+                        "schema": {
+                            "$ref": "#/definitions/Tasks"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID задачи или не удалось определить исполнителя.",
                         "schema": {
                             "type": "object",
                             "properties": {
-                                "user_id": {
+                                "error": {
                                     "type": "string",
-                                    "format": "uuid",
-                                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                                },
-                                "msg": {
+                                    "example": "На задачу назначено несколько пользователей, укажите user_id"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Запуск таймера от имени другого пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
                                     "type": "string",
-                                    "example": "Удаление пользователя прошло успешно"
+                                    "example": "Нельзя запускать таймер от имени другого пользователя"
                                 }
                             }
                         }
                     },
-                    "400": {
-                        "description": "Ошибка в запросе, например, неверный формат параметров или ошибка при удалении пользователя.",
+                    "404": {
+                        "description": "Задача не найдена.",
                         "schema": {
                             "type": "object",
                             "properties": {
                                 "error": {
                                     "type": "string",
-                                    "example": "Не удалось удалить пользователя: текст ошибки"
+                                    "example": "Задача не найдена"
                                 }
                             }
                         }
                     },
+                    "409": {
+                        "description": "Недопустимый переход состояния задачи.",
+                        "schema": {
+                            "$ref": "#/definitions/StateConflict"
+                        }
+                    },
                     "500": {
-                        "description": "Внутренняя ошибка сервера, например, ошибка при обработке запроса.",
+                        "description": "Ошибка при обновлении задачи.",
                         "schema": {
                             "type": "object",
                             "properties": {
                                 "error": {
                                     "type": "string",
-                                    "example": "Внутренняя ошибка сервера: текст ошибки"
+                                    "example": "Ошибка при обновлении задачи: текст ошибки"
                                 }
                             }
                         }
@@ -292,16 +475,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/get/{id}": {
-            "get": {
-                "summary": "Получение пользователя по ID",
-                "description": "Получает данные пользователя по указанному ID.",
-                "operationId": "getUserByID",
+        "/tasks/cancel/{id}": {
+            "post": {
+                "summary": "Отмена задачи",
+                "description": "Переводит задачу в состояние cancelled. Открытая сессия таймера закрывается.",
+                "operationId": "cancelTask",
                 "parameters": [
                     {
                         "name": "id",
                         "in": "path",
-                        "description": "ID пользователя для получения данных.",
+                        "description": "ID задачи для отмены.",
                         "required": true,
                         "type": "string",
                         "format": "uuid"
@@ -309,56 +492,49 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное получение данных пользователя.",
+                        "description": "Успешная отмена задачи.",
+                        "schema": {
+                            "$ref": "#/definitions/Tasks"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID задачи.",
                         "schema": {
                             "type": "object",
                             "properties": {
-                                "ID": {
-                                    "type": "string",
-                                    "format": "uuid",
-                                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                                },
-                                "Name": {
-                                    "type": "string",
-                                    "example": "John Doe"
-                                },
-                                "Email": {
-                                    "type": "string",
-                                    "example": "john.doe@example.com"
-                                },
-                                "CreatedAt": {
-                                    "type": "string",
-                                    "format": "date-time",
-                                    "example": "2023-07-01T00:00:00Z"
-                                },
-                                "UpdatedAt": {
+                                "error": {
                                     "type": "string",
-                                    "format": "date-time",
-                                    "example": "2023-07-01T00:00:00Z"
+                                    "example": "Некорректный ID задачи: текст ошибки"
                                 }
                             }
                         }
                     },
                     "404": {
-                        "description": "Пользователь не найден.",
+                        "description": "Задача не найдена.",
                         "schema": {
                             "type": "object",
                             "properties": {
                                 "error": {
                                     "type": "string",
-                                    "example": "Пользователь не найден"
+                                    "example": "Задача не найдена"
                                 }
                             }
                         }
                     },
+                    "409": {
+                        "description": "Недопустимый переход состояния задачи.",
+                        "schema": {
+                            "$ref": "#/definitions/StateConflict"
+                        }
+                    },
                     "500": {
-                        "description": "Внутренняя ошибка сервера, например, ошибка при получении данных пользователя.",
+                        "description": "Ошибка при обновлении задачи.",
                         "schema": {
                             "type": "object",
                             "properties": {
                                 "error": {
                                     "type": "string",
-                                    "example": "Ошибка при получении данных пользователя: текст ошибки"
+                                    "example": "Ошибка при обновлении задачи: текст ошибки"
                                 }
                             }
                         }
@@ -366,68 +542,112 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/update/{id}": {
-            "put": {
-                "summary": "Обновление данных пользователя",
-                "description": "Обновляет данные пользователя по указанному ID.",
-                "operationId": "updateUserByID",
+        "/tasks/history/{id}": {
+            "get": {
+                "summary": "История состояний задачи",
+                "description": "Возвращает все переходы задачи между состояниями в порядке их совершения.",
+                "operationId": "getTaskHistory",
                 "parameters": [
                     {
                         "name": "id",
                         "in": "path",
-                        "description": "ID пользователя для обновления данных.",
+                        "description": "ID задачи.",
                         "required": true,
                         "type": "string",
                         "format": "uuid"
-                    },
-                    {
-                        "in": "body",
-                        "name": "body",
-                        "description": "Обновленные данные пользователя.",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/Users"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное обновление данных пользователя.",
+                        "description": "Успешное получение истории.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/TaskStateTransition"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID задачи.",
                         "schema": {
                             "type": "object",
                             "properties": {
-                                "user_id": {
+                                "error": {
                                     "type": "string",
-                                    "format": "uuid",
-                                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                                },
-                                "msg": {
+                                    "example": "Некорректный ID задачи: текст ошибки"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
                                     "type": "string",
-                                    "example": "Обновление данных пользователя прошло успешно"
+                                    "example": "Задача не найдена"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Не удалось получить историю состояний задачи.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось получить историю состояний задачи: текст ошибки"
                                 }
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/tasks/get/{id}": {
+            "get": {
+                "summary": "Получение задачи по ID",
+                "description": "Получает данные задачи по указанному ID. Удалённые задачи не возвращаются.",
+                "operationId": "getTaskByID",
+                "parameters": [
+                    {
+                        "name": "id",
+                        "in": "path",
+                        "description": "ID задачи.",
+                        "required": true,
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное получение задачи.",
+                        "schema": {
+                            "$ref": "#/definitions/Tasks"
+                        }
                     },
                     "400": {
-                        "description": "Ошибка в запросе, например, неверный формат параметров или ошибка при обновлении данных пользователя.",
+                        "description": "Некорректный ID задачи.",
                         "schema": {
                             "type": "object",
                             "properties": {
                                 "error": {
                                     "type": "string",
-                                    "example": "Не удалось обновить данные пользователя: текст ошибки"
+                                    "example": "Некорректный ID задачи: текст ошибки"
                                 }
                             }
                         }
                     },
-                    "500": {
-                        "description": "Внутренняя ошибка сервера, например, ошибка при обработке запроса.",
+                    "404": {
+                        "description": "Задача не найдена.",
                         "schema": {
                             "type": "object",
                             "properties": {
                                 "error": {
                                     "type": "string",
-                                    "example": "Внутренняя ошибка сервера: текст ошибки"
+                                    "example": "Задача не найдена"
                                 }
                             }
                         }
@@ -435,48 +655,179 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/laborCost/{user_id}": {
+        "/tasks/list": {
             "post": {
-                "summary": "Получение трудозатрат пользователя",
-                "description": "Получает трудозатраты пользователя за определённый период времени.",
-                "operationId": "getUserLaborCost",
+                "summary": "Получение списка задач",
+                "description": "Получает список задач с возможностью фильтрации и пагинации. Фильтрация доступна по полям name, description и state. Задачи идут по дате создания по возрастанию в обоих режимах пагинации.",
+                "operationId": "getTasks",
                 "parameters": [
                     {
-                        "name": "user_id",
+                        "name": "input",
+                        "in": "body",
+                        "description": "Параметры фильтрации и пагинации.",
+                        "required": false,
+                        "schema": {
+                            "$ref": "#/definitions/TaskGetListInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное получение списка задач.",
+                        "schema": {
+                            "$ref": "#/definitions/TaskListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры фильтрации.",
+                        "schema": {
+                            "$ref": "#/definitions/FilterError"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/update/{id}": {
+            "put": {
+                "summary": "Обновление задачи",
+                "description": "Обновляет название и описание задачи по указанному ID.",
+                "operationId": "updateTaskByID",
+                "parameters": [
+                    {
+                        "name": "id",
                         "in": "path",
-                        "description": "ID пользователя для получения трудозатрат.",
+                        "description": "ID задачи для обновления.",
                         "required": true,
                         "type": "string",
                         "format": "uuid"
                     },
                     {
-                        "name": "period",
+                        "name": "body",
                         "in": "body",
-                        "description": "Период времени для расчета трудозатрат.",
+                        "description": "Новые название и описание задачи.",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/Period"
+                            "$ref": "#/definitions/TasksUpdateInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное получение трудозатрат пользователя.",
+                        "description": "Успешное обновление задачи.",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/TaskResponse"
+                            "$ref": "#/definitions/Tasks"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID задачи или данные задачи не прошли валидацию.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Данные задачи не прошли валидацию: У задачи отсутствует название!"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Задача не найдена"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Не удалось обновить данные задачи.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось обновить данные задачи: текст ошибки"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/delete/{id}": {
+            "delete": {
+                "summary": "Удаление задачи",
+                "description": "Мягко удаляет задачу: запись помечается DeletedAt, запущенный таймер останавливается, а задача в работе или на паузе переводится в cancelled.",
+                "operationId": "deleteTaskByID",
+                "parameters": [
+                    {
+                        "name": "id",
+                        "in": "path",
+                        "description": "ID задачи для удаления.",
+                        "required": true,
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное удаление задачи.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "task_id": {
+                                    "type": "string",
+                                    "format": "uuid",
+                                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                                },
+                                "msg": {
+                                    "type": "string",
+                                    "example": "Удаление задачи прошло успешно"
+                                }
                             }
                         }
                     },
                     "400": {
-                        "description": "Не удалось получить трудозатраты пользователя.",
+                        "description": "Некорректный ID задачи.",
                         "schema": {
                             "type": "object",
                             "properties": {
                                 "error": {
                                     "type": "string",
-                                    "example": "Не удалось получить трудозатраты пользователя: текст ошибки"
+                                    "example": "Некорректный ID задачи: текст ошибки"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Задача не найдена"
+                                }
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Задачу нельзя отменить из текущего состояния.",
+                        "schema": {
+                            "$ref": "#/definitions/StateConflict"
+                        }
+                    },
+                    "500": {
+                        "description": "Не удалось удалить задачу.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось удалить задачу: текст ошибки"
                                 }
                             }
                         }
@@ -484,262 +835,2452 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/list": {
+        "/tasks/assign/{id}": {
             "post": {
-                "summary": "Получение списка пользователей",
-                "description": "Получает список пользователей с возможностью фильтрации и пагинации.",
-                "operationId": "getUsers",
+                "summary": "Назначение пользователя на задачу",
+                "description": "Добавляет исполнителя задачи. У задачи может быть несколько исполнителей.",
+                "operationId": "assignUser",
                 "parameters": [
                     {
-                        "name": "input",
+                        "name": "id",
+                        "in": "path",
+                        "description": "ID задачи.",
+                        "required": true,
+                        "type": "string",
+                        "format": "uuid"
+                    },
+                    {
+                        "name": "body",
                         "in": "body",
-                        "description": "Параметры фильтрации и пагинации.",
-                        "required": false,
+                        "description": "Пользователь для назначения.",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/UserGetListInput"
+                            "$ref": "#/definitions/AssignInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное получение списка пользователей.",
+                        "description": "Пользователь назначен.",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/Users"
+                            "type": "object",
+                            "properties": {
+                                "task_id": {
+                                    "type": "string",
+                                    "format": "uuid",
+                                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                                },
+                                "user_id": {
+                                    "type": "string",
+                                    "format": "uuid",
+                                    "example": "550e8400-e29b-41d4-a716-446655440001"
+                                },
+                                "msg": {
+                                    "type": "string",
+                                    "example": "Пользователь назначен на задачу"
+                                }
                             }
                         }
                     },
                     "400": {
-                        "description": "Не удалось получить список пользователей.",
+                        "description": "Ошибка в запросе, например, некорректный ID задачи.",
                         "schema": {
                             "type": "object",
                             "properties": {
                                 "error": {
                                     "type": "string",
-                                    "example": "Не удалось получить список пользователей: текст ошибки"
+                                    "example": "Не удалось декодировать тело запроса: текст ошибки"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Задача или пользователь не найдены.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Задача не найдена"
+                                }
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Пользователь уже назначен на задачу.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Пользователь уже назначен на задачу"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Не удалось назначить пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось назначить пользователя: текст ошибки"
                                 }
                             }
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "Users": {
+        },
+        "/tasks/unassign/{id}/{user_id}": {
+            "delete": {
+                "summary": "Снятие пользователя с задачи",
+                "description": "Снимает исполнителя с задачи. Если у него был запущен таймер, сессия закрывается, а задача ставится на паузу.",
+                "operationId": "unassignUser",
+                "parameters": [
+                    {
+                        "name": "id",
+                        "in": "path",
+                        "description": "ID задачи.",
+                        "required": true,
+                        "type": "string",
+                        "format": "uuid"
+                    },
+                    {
+                        "name": "user_id",
+                        "in": "path",
+                        "description": "ID пользователя.",
+                        "required": true,
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователь снят с задачи.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "task_id": {
+                                    "type": "string",
+                                    "format": "uuid",
+                                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                                },
+                                "user_id": {
+                                    "type": "string",
+                                    "format": "uuid",
+                                    "example": "550e8400-e29b-41d4-a716-446655440001"
+                                },
+                                "msg": {
+                                    "type": "string",
+                                    "example": "Пользователь снят с задачи"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID задачи или пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Некорректный ID пользователя: текст ошибки"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена или пользователь не назначен на неё.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Пользователь не назначен на задачу"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Не удалось снять пользователя с задачи.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось снять пользователя с задачи: текст ошибки"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/transfer/{id}": {
+            "post": {
+                "summary": "Передача задачи другому пользователю",
+                "description": "Снимает задачу с одного исполнителя и назначает на другого. Запущенный таймер продолжается, но новая сессия записывается на нового исполнителя.",
+                "operationId": "transferTask",
+                "parameters": [
+                    {
+                        "name": "id",
+                        "in": "path",
+                        "description": "ID задачи.",
+                        "required": true,
+                        "type": "string",
+                        "format": "uuid"
+                    },
+                    {
+                        "name": "body",
+                        "in": "body",
+                        "description": "Исполнители задачи до и после передачи.",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/TransferInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача передана.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "task_id": {
+                                    "type": "string",
+                                    "format": "uuid",
+                                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                                },
+                                "user_id": {
+                                    "type": "string",
+                                    "format": "uuid",
+                                    "example": "550e8400-e29b-41d4-a716-446655440001"
+                                },
+                                "msg": {
+                                    "type": "string",
+                                    "example": "Задача передана пользователю"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в запросе, например, некорректный ID задачи.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Задача передаётся тому же пользователю"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Задача или пользователь не найдены.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Задача не найдена"
+                                }
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Новый исполнитель уже назначен на задачу.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Пользователь уже назначен на задачу"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Не удалось передать задачу.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось передать задачу: текст ошибки"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/create": {
+            "post": {
+                "summary": "Создание нового пользователя",
+                "description": "Создает нового пользователя с указанными данными. Если передан только passportNumber в формате \"1234 567890\", ФИО и адрес запрашиваются во внешнем сервисе по паспорту.",
+                "operationId": "createUser",
+                "parameters": [
+                    {
+                        "in": "body",
+                        "name": "body",
+                        "description": "Данные нового пользователя.",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UsersCreateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное создание пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user_id": {
+                                    "type": "string",
+                                    "format": "uuid",
+                                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                                },
+                                "msg": {
+                                    "type": "string",
+                                    "example": "Создание пользователя прошло успешно"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в запросе, например, неверный формат параметров или ошибка при декодировании данных.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось создать пользователя: текст ошибки"
+                                }
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Паспорт уже принадлежит другому пользователю.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Пользователь с таким паспортом уже существует"
+                                }
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Внешний сервис не нашел данных по паспорту.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Сервис не нашел данных по паспорту"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера, например, ошибка при создании пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось создать пользователя: текст ошибки"
+                                }
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Внешний сервис получения данных по паспорту недоступен.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось получить данные пользователя по паспорту: Сервис получения данных по паспорту недоступен: сервис ответил 500 Internal Server Error"
+                                }
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Получение данных по паспорту не настроено.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Сервис получения данных по паспорту не настроен"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/delete/{id}": {
+            "delete": {
+                "summary": "Удаление пользователя по ID",
+                "description": "Мягко удаляет пользователя с указанным ID. Пользователь снимается со всех задач, его запущенные таймеры останавливаются, а задачи ставятся на паузу.",
+                "operationId": "deleteUserById",
+                "parameters": [
+                    {
+                        "name": "id",
+                        "in": "path",
+                        "description": "ID пользователя для удаления.",
+                        "required": true,
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное удаление пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user_id": {
+                                    "type": "string",
+                                    "format": "uuid",
+                                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                                },
+                                "rows_affected": {
+                                    "type": "string",
+                                    "example": "1"
+                                },
+                                "msg": {
+                                    "type": "string",
+                                    "example": "Удаление пользователя прошло успешно"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в запросе, например, неверный формат параметров или ошибка при удалении пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось удалить пользователя: текст ошибки"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Пользователь не найден"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера, например, ошибка при обработке запроса.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Внутренняя ошибка сервера: текст ошибки"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/restore/{id}": {
+            "post": {
+                "summary": "Восстановление пользователя по ID",
+                "description": "Восстанавливает удаленного пользователя и его связи с задачами, снятые при удалении. Задачи, поставленные тогда на паузу, остаются на паузе.",
+                "operationId": "restoreUserByID",
+                "parameters": [
+                    {
+                        "name": "id",
+                        "in": "path",
+                        "description": "ID пользователя для восстановления.",
+                        "required": true,
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное восстановление пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user_id": {
+                                    "type": "string",
+                                    "format": "uuid",
+                                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                                },
+                                "tasks_restored": {
+                                    "type": "string",
+                                    "example": "2"
+                                },
+                                "msg": {
+                                    "type": "string",
+                                    "example": "Восстановление пользователя прошло успешно"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Некорректный ID пользователя: invalid UUID length: 3"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Пользователь не найден"
+                                }
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Пользователь не удален.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Пользователь не удален"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось восстановить пользователя: текст ошибки"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password/{id}": {
+            "post": {
+                "summary": "Установка пароля пользователя",
+                "description": "Задает пароль для входа. Доступно самому пользователю и администратору. Пока ни у одного пользователя нет пароля, вместо токена можно передать AUTH_BOOTSTRAP_SECRET.",
+                "operationId": "setUserPassword",
+                "parameters": [
+                    {
+                        "name": "id",
+                        "in": "path",
+                        "description": "ID пользователя.",
+                        "required": true,
+                        "type": "string",
+                        "format": "uuid"
+                    },
+                    {
+                        "name": "body",
+                        "in": "body",
+                        "description": "Новый пароль, не короче 8 символов.",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UsersPasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пароль установлен.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user_id": {
+                                    "type": "string",
+                                    "format": "uuid",
+                                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                                },
+                                "msg": {
+                                    "type": "string",
+                                    "example": "Пароль пользователя установлен"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или пароль.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Пароль должен быть не короче 8 символов"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Требуется авторизация"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Недостаточно прав для выполнения запроса"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Пользователь не найден"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/get/{id}": {
+            "get": {
+                "summary": "Получение пользователя по ID",
+                "description": "Получает данные пользователя по указанному ID. Паспортные данные возвращаются замаскированными, например 45** ****56.",
+                "operationId": "getUserByID",
+                "parameters": [
+                    {
+                        "name": "reveal",
+                        "in": "query",
+                        "description": "true - вернуть паспортные данные без маски. Доступно только администратору.",
+                        "required": false,
+                        "type": "boolean"
+                    },
+                    {
+                        "name": "id",
+                        "in": "path",
+                        "description": "ID пользователя для получения данных.",
+                        "required": true,
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное получение данных пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "ID": {
+                                    "type": "string",
+                                    "format": "uuid",
+                                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                                },
+                                "Name": {
+                                    "type": "string",
+                                    "example": "John Doe"
+                                },
+                                "Email": {
+                                    "type": "string",
+                                    "example": "john.doe@example.com"
+                                },
+                                "CreatedAt": {
+                                    "type": "string",
+                                    "format": "date-time",
+                                    "example": "2023-07-01T00:00:00Z"
+                                },
+                                "UpdatedAt": {
+                                    "type": "string",
+                                    "format": "date-time",
+                                    "example": "2023-07-01T00:00:00Z"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Некорректный ID пользователя: invalid UUID length: 3"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Пользователь не найден"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера, например, ошибка при получении данных пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Ошибка при получении данных пользователя: текст ошибки"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/update/{id}": {
+            "put": {
+                "summary": "Обновление данных пользователя",
+                "description": "Обновляет данные пользователя по указанному ID.",
+                "operationId": "updateUserByID",
+                "parameters": [
+                    {
+                        "name": "id",
+                        "in": "path",
+                        "description": "ID пользователя для обновления данных.",
+                        "required": true,
+                        "type": "string",
+                        "format": "uuid"
+                    },
+                    {
+                        "in": "body",
+                        "name": "body",
+                        "description": "Обновленные данные пользователя.",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Users"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное обновление данных пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user_id": {
+                                    "type": "string",
+                                    "format": "uuid",
+                                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                                },
+                                "rows_affected": {
+                                    "type": "string",
+                                    "example": "1"
+                                },
+                                "msg": {
+                                    "type": "string",
+                                    "example": "Обновление данных пользователя прошло успешно"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в запросе, например, неверный формат параметров или ошибка при обновлении данных пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось обновить данные пользователя: текст ошибки"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Пользователь не найден"
+                                }
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Паспорт уже принадлежит другому пользователю.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Пользователь с таким паспортом уже существует"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера, например, ошибка при обработке запроса.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Внутренняя ошибка сервера: текст ошибки"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "patch": {
+                "summary": "Частичное обновление данных пользователя",
+                "description": "Обновляет только переданные поля пользователя. Полный номер паспорта пересчитывается, если изменилась серия или номер.",
+                "operationId": "patchUserByID",
+                "parameters": [
+                    {
+                        "name": "id",
+                        "in": "path",
+                        "description": "ID пользователя для обновления данных.",
+                        "required": true,
+                        "type": "string",
+                        "format": "uuid"
+                    },
+                    {
+                        "name": "body",
+                        "in": "body",
+                        "description": "Поля пользователя для обновления.",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UsersPatchInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное обновление данных пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user_id": {
+                                    "type": "string",
+                                    "format": "uuid",
+                                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                                },
+                                "rows_affected": {
+                                    "type": "string",
+                                    "example": "1"
+                                },
+                                "msg": {
+                                    "type": "string",
+                                    "example": "Обновление данных пользователя прошло успешно"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID, пустой запрос или данные не прошли валидацию.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Данные пользователя не прошли валидацию: текст ошибки"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Пользователь не найден"
+                                }
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Паспорт уже принадлежит другому пользователю.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Пользователь с таким паспортом уже существует"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось получить данные пользователя: текст ошибки"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/laborCost/{user_id}": {
+            "post": {
+                "summary": "Получение трудозатрат пользователя",
+                "description": "Получает трудозатраты пользователя за определённый период времени. Сессии, выходящие за границы периода, обрезаются по ним. С include_running учитываются запущенные таймеры до текущего момента.",
+                "operationId": "getUserLaborCost",
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": ["application/json", "text/csv", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"],
+                "parameters": [
+                    {
+                        "name": "user_id",
+                        "in": "path",
+                        "description": "ID пользователя для получения трудозатрат.",
+                        "required": true,
+                        "type": "string",
+                        "format": "uuid"
+                    },
+                    {
+                        "name": "period",
+                        "in": "body",
+                        "description": "Период времени для расчета трудозатрат.",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/LaborCostInput"
+                        }
+                    },
+                    {
+                        "name": "format",
+                        "in": "query",
+                        "description": "Формат ответа: json (по умолчанию), csv или xlsx. Вместо параметра можно передать заголовок Accept: text/csv или application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.",
+                        "required": false,
+                        "type": "string",
+                        "enum": [
+                            "json",
+                            "csv",
+                            "xlsx"
+                        ]
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное получение трудозатрат пользователя.",
+                        "schema": {
+                            "$ref": "#/definitions/LaborCostReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Не удалось получить трудозатраты пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось получить трудозатраты пользователя: текст ошибки"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Пользователь не найден"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/list": {
+            "post": {
+                "summary": "Получение списка пользователей",
+                "description": "Получает список пользователей с возможностью фильтрации и пагинации. Без явной сортировки и поиска пользователи идут по дате создания по возрастанию, в режиме курсора - всегда. Паспортные данные возвращаются замаскированными, например 45** ****56.",
+                "operationId": "getUsers",
+                "parameters": [
+                    {
+                        "name": "reveal",
+                        "in": "query",
+                        "description": "true - вернуть паспортные данные без маски. Доступно только администратору.",
+                        "required": false,
+                        "type": "boolean"
+                    },
+                    {
+                        "name": "input",
+                        "in": "body",
+                        "description": "Параметры фильтрации и пагинации.",
+                        "required": false,
+                        "schema": {
+                            "$ref": "#/definitions/UserGetListInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное получение списка пользователей.",
+                        "schema": {
+                            "$ref": "#/definitions/UserListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Не удалось получить список пользователей.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось получить список пользователей: текст ошибки"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/tasks": {
+            "get": {
+                "summary": "Задачи пользователя",
+                "description": "Возвращает все задачи, назначенные на пользователя, с их состоянием и временем, отработанным по ним этим пользователем. Время других исполнителей задачи не учитывается. Время запущенного таймера учитывается на момент запроса.",
+                "operationId": "getUserTasks",
+                "parameters": [
+                    {
+                        "name": "user_id",
+                        "in": "path",
+                        "description": "ID пользователя.",
+                        "required": true,
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное получение задач пользователя.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/UserTaskResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Некорректный ID пользователя: invalid UUID length: 3"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Пользователь не найден"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Не удалось получить задачи пользователя.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось получить задачи пользователя: текст ошибки"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/laborCost": {
+            "post": {
+                "summary": "Отчет по трудозатратам команды",
+                "description": "Считает трудозатраты за период для всех пользователей, подходящих под фильтр (синтаксис фильтров как в /users/list). Для каждого пользователя возвращаются итог и разбивка по задачам.",
+                "operationId": "teamLaborCost",
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": ["application/json", "text/csv", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"],
+                "parameters": [
+                    {
+                        "name": "input",
+                        "in": "body",
+                        "description": "Период и фильтр пользователей.",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/TeamLaborCostInput"
+                        }
+                    },
+                    {
+                        "name": "format",
+                        "in": "query",
+                        "description": "Формат ответа: json (по умолчанию), csv или xlsx. Вместо параметра можно передать заголовок Accept: text/csv или application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.",
+                        "required": false,
+                        "type": "string",
+                        "enum": [
+                            "json",
+                            "csv",
+                            "xlsx"
+                        ]
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное получение отчета.",
+                        "schema": {
+                            "$ref": "#/definitions/TeamLaborCostReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры отчета.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Конец периода должен быть позже начала"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Не удалось получить трудозатраты команды.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось получить трудозатраты команды: текст ошибки"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/apiKeys/create": {
+            "post": {
+                "summary": "Создание ключа API",
+                "description": "Создает ключ API с указанными областями доступа. Ключ целиком возвращается только в этом ответе, в базе хранится лишь его хеш. Доступно только администратору.",
+                "operationId": "createAPIKey",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "name": "body",
+                        "in": "body",
+                        "description": "Название ключа и области доступа.",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/APIKeysCreateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Ключ создан.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "apiKey": {
+                                    "$ref": "#/definitions/APIKey"
+                                },
+                                "key": {
+                                    "type": "string",
+                                    "example": "emk_Qm9vdHN0cmFwU2VjcmV0S2V5RXhhbXBsZTEyMzQ1Ng"
+                                },
+                                "msg": {
+                                    "type": "string",
+                                    "example": "Ключ API создан. Сохраните его, повторно он показан не будет"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные название или области доступа.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Данные ключа API не прошли валидацию: Неизвестная область доступа \"users:write\"!"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан токен или ключ API.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Требуется авторизация"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав, доступно только администратору.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Недостаточно прав"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось сохранить ключ API: ..."
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/apiKeys/list": {
+            "get": {
+                "summary": "Список ключей API",
+                "description": "Возвращает действующие ключи API с временем последнего использования. Доступно только администратору.",
+                "operationId": "getAPIKeys",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "name": "includeRevoked",
+                        "in": "query",
+                        "description": "Включить отозванные ключи.",
+                        "required": false,
+                        "type": "boolean"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список ключей.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/APIKey"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан токен или ключ API.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Требуется авторизация"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав, доступно только администратору.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Недостаточно прав"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось получить список ключей API: ..."
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/apiKeys/revoke/{id}": {
+            "post": {
+                "summary": "Отзыв ключа API",
+                "description": "Отзывает ключ API. Запросы с ним сразу начинают получать 401. Доступно только администратору.",
+                "operationId": "revokeAPIKey",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "name": "id",
+                        "in": "path",
+                        "description": "ID ключа API.",
+                        "required": true,
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ключ отозван.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "api_key_id": {
+                                    "type": "string",
+                                    "format": "uuid",
+                                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                                },
+                                "msg": {
+                                    "type": "string",
+                                    "example": "Ключ API отозван"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID ключа.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Некорректный ID ключа API: invalid UUID length: 3"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан токен или ключ API.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Требуется авторизация"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав, доступно только администратору.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Недостаточно прав"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Ключ не найден.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Ключ API не найден"
+                                }
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Ключ уже отозван.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Ключ API уже отозван"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера.",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string",
+                                    "example": "Не удалось отозвать ключ API: ..."
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "UsersCreateInput": {
+            "type": "object",
+            "description": "Данные нового пользователя. Роль и руководителя назначает администратор через PATCH /users/{id}, остальные поля в теле игнорируются.",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Иван"
+                },
+                "surname": {
+                    "type": "string",
+                    "example": "Иванов"
+                },
+                "patronymic": {
+                    "type": "string",
+                    "example": "Викторович"
+                },
+                "address": {
+                    "type": "string",
+                    "example": "г.Ростов-на-Дону, ул.Извилистая 25/341"
+                },
+                "passportSerie": {
+                    "type": "string",
+                    "example": "1234"
+                },
+                "passportNumber": {
+                    "type": "string",
+                    "example": "567890"
+                }
+            },
+            "required": ["name", "surname", "patronymic","address", "passportSerie", "passportNumber"]
+        },
+        "Users": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Иван"
+                },
+                "surname": {
+                    "type": "string",
+                    "example": "Иванов"
+                },
+                "patronymic": {
+                    "type": "string",
+                    "example": "Викторович"
+                },
+                "address": {
+                    "type": "string",
+                    "example": "г.Ростов-на-Дону, ул.Извилистая 25/341"
+                },
+                "passportSerie": {
+                    "type": "string",
+                    "example": "1234"
+                },
+                "passportNumber": {
+                    "type": "string",
+                    "example": "567890"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": ["admin", "manager", "employee"],
+                    "example": "employee"
+                },
+                "managerID": {
+                    "type": "string",
+                    "format": "uuid",
+                    "description": "Руководитель пользователя: пользователь с ролью manager или admin.",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "deletedAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "description": "null у неудаленных пользователей.",
+                    "readOnly": true
+                }
+            },
+            "required": ["name", "surname", "patronymic","address", "passportSerie", "passportNumber"]
+        },
+        "Tasks": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Задача 1"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Описание задачи 1"
+                },
+                "state": {
+                    "type": "string",
+                    "enum": ["new", "in_progress", "paused", "done", "cancelled"],
+                    "example": "in_progress"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-07-07T12:00:00Z"
+                },
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-07-07T14:00:00Z"
+                },
+                "duration": {
+                    "type": "integer",
+                    "example": 7200
+                }
+            },
+            "required": ["title", "user_id"]
+        },
+        "TaskResponse": {
+            "type": "object",
+            "properties": {
+                "task_id": {
+                    "type": "string",
+                    "example": "f5b8cb9f-3c5c-11ef-9578-0250a55f1078"
+                },
+				"name": {
+                    "type": "string",
+                    "example": "Создать описание API"
+                },
+				"hours": {
+                    "type": "string",
+                    "example": "2"
+                },
+				"minutes": {
+                    "type": "string",
+                    "example": "1"
+                },
+				"seconds": {
+                    "type": "string",
+                    "example": "54"
+                },
+                "percent": {
+                    "type": "number",
+                    "example": 37.5
+                }
+            }
+        },
+        "TasksCreateInput": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Новая задача"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Описание новой задачи"
+                }
+            },
+            "required": ["name"]
+        },
+        "UsersTasks": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440001"
+                },
+                "task_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440002"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-07-01T10:00:00Z"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-07-05T15:30:00Z"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-07-05T15:30:00Z"
+                }
+            }
+        },
+        "UserFilter": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "enum": ["id", "name", "surname", "patronymic", "address", "createdAt", "updatedAt", "deletedAt"],
+                    "example": "name"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "С"
+                },
+                "operator": {
+                    "type": "string",
+                    "enum": ["equals", "notEquals", "contains", "startsWith", "endsWith", "in", "gt", "gte", "lt", "lte", "between", "isNull"],
+                    "example": "startsWith"
+                },
+                "values": {
+                    "type": "array",
+                    "description": "Значения для операторов in и between.",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": ["2024-06-01T00:00:00Z", "2024-06-30T23:59:59Z"]
+                },
+                "caseInsensitive": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "UserFilters": {
+            "type": "object",
+            "description": "Условия из filters объединяются через AND. Все группы из and должны выполняться, из or - хотя бы одна.",
+            "properties": {
+                "filters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/UserFilter"
+                    }
+                },
+                "and": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/UserFilters"
+                    }
+                },
+                "or": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/UserFilters"
+                    }
+                }
+            }
+        },
+        "UserGetListInput": {
+            "type": "object",
+            "properties": {
+                "includeDeleted": {
+                    "type": "boolean",
+                    "description": "Включать в выборку удаленных пользователей.",
+                    "example": false
+                },
+                "q": {
+                    "type": "string",
+                    "description": "Полнотекстовый поиск по имени, фамилии, отчеству и адресу. Без sort результаты упорядочены по релевантности. Недоступен в режиме курсора.",
+                    "example": "Иванов Москва"
+                },
+				"page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "limit": {
+                    "type": "integer",
+                    "description": "Размер страницы: по умолчанию 10, не больше 100. Больший limit урезается до 100.",
+                    "example": 3
+                },
+                "sort": {
+                    "type": "array",
+                    "description": "Недоступна в режиме курсора.",
+                    "items": {
+                        "$ref": "#/definitions/UserSort"
+                    }
+                },
+                "pagination": {
+                    "type": "string",
+                    "enum": ["offset", "cursor"],
+                    "example": "cursor"
+                },
+                "cursor": {
+                    "type": "string",
+                    "description": "nextCursor из предыдущего ответа. Передача курсора включает режим курсора.",
+                    "example": "eyJjIjoiMjAyNC0wNy0wMVQxMDowMDowMFoiLCJpIjoiNTUwZTg0MDAtZTI5Yi00MWQ0LWE3MTYtNDQ2NjU1NDQwMDAwIn0"
+                },
+                "filters": {
+                    "$ref": "#/definitions/UserFilters",
+					"example": {"field": "name", "value": "С", "operator": "startsWith"}
+                }
+            }
+        },
+        "Period": {
+            "type": "object",
+            "properties": {
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-07-03T00:00:00Z"
+                },
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example":"2024-07-04T23:59:59Z"
+                }
+            }
+        },
+        "StateConflict": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Недопустимый переход задачи из состояния done в in_progress"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "done"
+                }
+            }
+        },
+        "TaskStateTransition": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "taskID": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440001"
+                },
+                "fromState": {
+                    "type": "string",
+                    "example": "new"
+                },
+                "toState": {
+                    "type": "string",
+                    "example": "in_progress"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-07-07T12:00:00Z"
+                },
+                "actorID": {
+                    "type": "string",
+                    "format": "uuid",
+                    "description": "Пользователь, выполнивший переход.",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                }
+            }
+        },
+        "TasksUpdateInput": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Переименованная задача"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Новое описание задачи"
+                }
+            },
+            "required": [
+                "name"
+            ]
+        },
+        "TaskFilter": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "enum": [
+                        "id",
+                        "name",
+                        "description",
+                        "state",
+                        "createdAt",
+                        "updatedAt"
+                    ],
+                    "example": "state"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "in_progress"
+                },
+                "operator": {
+                    "type": "string",
+                    "example": "equals"
+                }
+            }
+        },
+        "TaskGetListInput": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "limit": {
+                    "type": "integer",
+                    "description": "Размер страницы: по умолчанию 10, не больше 100. Больший limit урезается до 100.",
+                    "example": 10
+                },
+                "pagination": {
+                    "type": "string",
+                    "enum": ["offset", "cursor"],
+                    "example": "offset"
+                },
+                "cursor": {
+                    "type": "string",
+                    "example": ""
+                },
+                "filters": {
+                    "type": "object",
+                    "properties": {
+                        "filters": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/TaskFilter"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "UserTaskResponse": {
+            "type": "object",
+            "properties": {
+                "taskID": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "f5b8cb9f-3c5c-11ef-9578-0250a55f1078"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Создать описание API"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Описать все ручки в swagger"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "in_progress"
+                },
+                "hours": {
+                    "type": "integer",
+                    "description": "Время, отработанное по задаче этим пользователем.",
+                    "example": 2
+                },
+                "minutes": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "seconds": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "runningSince": {
+                    "type": "string",
+                    "format": "date-time",
+                    "description": "Начало текущей сессии пользователя, если таймер запущен им.",
+                    "example": "2024-07-07T12:00:00Z"
+                }
+            }
+        },
+        "AssignInput": {
+            "type": "object",
+            "properties": {
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440001"
+                }
+            },
+            "required": [
+                "user_id"
+            ]
+        },
+        "TransferInput": {
+            "type": "object",
+            "properties": {
+                "from_user_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440001"
+                },
+                "to_user_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440002"
+                }
+            },
+            "required": [
+                "from_user_id",
+                "to_user_id"
+            ]
+        },
+        "LaborCostInput": {
+            "type": "object",
+            "properties": {
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-07-01T00:00:00Z"
+                },
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-07-31T23:59:59Z"
+                },
+                "include_running": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "group_by": {
+                    "type": "string",
+                    "enum": ["day", "week"],
+                    "example": "week"
+                }
+            },
+            "required": [
+                "start_time",
+                "end_time"
+            ]
+        },
+        "Duration": {
+            "type": "object",
+            "properties": {
+                "hours": {
+                    "type": "integer",
+                    "example": 40
+                },
+                "minutes": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "seconds": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "Bucket": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string",
+                    "example": "2024-W27"
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-07-01T00:00:00Z"
+                },
+                "hours": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "minutes": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "percent": {
+                    "type": "number",
+                    "example": 21.2
+                }
+            }
+        },
+        "LaborCostReport": {
             "type": "object",
             "properties": {
-                "name": {
-                    "type": "string",
-                    "example": "Иван"
+                "total": {
+                    "$ref": "#/definitions/Duration"
                 },
-                "surname": {
-                    "type": "string",
-                    "example": "Иванов"
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/TaskResponse"
+                    }
                 },
-                "patronymic": {
-                    "type": "string",
-                    "example": "Викторович"
+                "days": {
+                    "type": "array",
+                    "description": "Заполняется при group_by=day.",
+                    "items": {
+                        "$ref": "#/definitions/Bucket"
+                    }
                 },
-                "address": {
+                "weeks": {
+                    "type": "array",
+                    "description": "Заполняется при group_by=week.",
+                    "items": {
+                        "$ref": "#/definitions/Bucket"
+                    }
+                }
+            }
+        },
+        "TeamLaborCostInput": {
+            "type": "object",
+            "properties": {
+                "start_time": {
                     "type": "string",
-                    "example": "г.Ростов-на-Дону, ул.Извилистая 25/341"
+                    "format": "date-time",
+                    "example": "2024-07-01T00:00:00Z"
                 },
-                "passportSerie": {
+                "end_time": {
                     "type": "string",
-                    "example": "1234"
+                    "format": "date-time",
+                    "example": "2024-07-31T23:59:59Z"
                 },
-                "passportNumber": {
+                "include_running": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "group_by": {
                     "type": "string",
-                    "example": "567890"
+                    "enum": [
+                        "day",
+                        "week"
+                    ],
+                    "example": "week"
+                },
+                "filters": {
+                    "$ref": "#/definitions/UserFilters"
                 }
             },
-            "required": ["name", "surname", "patronymic","address", "passportSerie", "passportNumber"]
+            "required": [
+                "start_time",
+                "end_time"
+            ]
         },
-        "Tasks": {
+        "UserLaborCost": {
             "type": "object",
             "properties": {
-                "id": {
+                "userID": {
                     "type": "string",
                     "format": "uuid",
                     "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
                 "name": {
                     "type": "string",
-                    "example": "Задача 1"
+                    "example": "Иван"
                 },
-                "description": {
+                "surname": {
                     "type": "string",
-                    "example": "Описание задачи 1"
+                    "example": "Иванов"
                 },
-                "user_id": {
+                "patronymic": {
                     "type": "string",
-                    "format": "uuid",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                    "example": "Викторович"
                 },
-                "start_time": {
-                    "type": "string",
-                    "format": "date-time",
-                    "example": "2024-07-07T12:00:00Z"
+                "total": {
+                    "$ref": "#/definitions/Duration"
                 },
-                "end_time": {
-                    "type": "string",
-                    "format": "date-time",
-                    "example": "2024-07-07T14:00:00Z"
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/TaskResponse"
+                    }
                 },
-                "duration": {
-                    "type": "integer",
-                    "example": 7200
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Bucket"
+                    }
+                },
+                "weeks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Bucket"
+                    }
                 }
-            },
-            "required": ["title", "user_id"]
+            }
         },
-        "TaskResponse": {
+        "TeamLaborCostReport": {
             "type": "object",
             "properties": {
-                "task_id": {
-                    "type": "string",
-                    "example": "f5b8cb9f-3c5c-11ef-9578-0250a55f1078"
+                "total": {
+                    "$ref": "#/definitions/Duration"
                 },
-				"name": {
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/UserLaborCost"
+                    }
+                }
+            }
+        },
+        "FilterError": {
+            "type": "object",
+            "properties": {
+                "error": {
                     "type": "string",
-                    "example": "Создать описание API"
+                    "example": "Некорректное поле фильтрации: full_passport"
                 },
-				"hours": {
+                "field": {
                     "type": "string",
-                    "example": "2"
+                    "example": "full_passport"
                 },
-				"minutes": {
+                "operator": {
                     "type": "string",
-                    "example": "1"
+                    "example": "equals"
+                }
+            }
+        },
+        "UserSort": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "enum": [
+                        "id",
+                        "name",
+                        "surname",
+                        "patronymic",
+                        "address",
+                        "createdAt",
+                        "updatedAt",
+                        "deletedAt"
+                    ],
+                    "example": "surname"
                 },
-				"seconds": {
+                "direction": {
                     "type": "string",
-                    "example": "54"
+                    "enum": [
+                        "asc",
+                        "desc"
+                    ],
+                    "example": "asc"
                 }
             }
         },
-        "TasksCreateInput": {
+        "UserListResponse": {
             "type": "object",
             "properties": {
-                "name": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Users"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "description": "Не заполняется в режиме курсора.",
+                    "example": 117
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "hasNext": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "nextCursor": {
                     "type": "string",
-                    "example": "Новая задача"
+                    "description": "Заполняется только в режиме курсора, если есть следующая страница.",
+                    "example": "eyJjIjoiMjAyNC0wNy0wMVQxMDowMDowMFoiLCJpIjoiNTUwZTg0MDAtZTI5Yi00MWQ0LWE3MTYtNDQ2NjU1NDQwMDAwIn0"
+                }
+            }
+        },
+        "TaskListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Tasks"
+                    }
                 },
-                "description": {
+                "total": {
+                    "type": "integer",
+                    "description": "Не заполняется в режиме курсора.",
+                    "example": 42
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "hasNext": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "nextCursor": {
                     "type": "string",
-                    "example": "Описание новой задачи"
+                    "description": "Заполняется только в режиме курсора, если есть следующая страница.",
+                    "example": "eyJjIjoiMjAyNC0wNy0wMVQxMDowMDowMFoiLCJpIjoiNTUwZTg0MDAtZTI5Yi00MWQ0LWE3MTYtNDQ2NjU1NDQwMDAwIn0"
                 }
-            },
-            "required": ["name"]
+            }
         },
-        "UsersTasks": {
+        "UsersPatchInput": {
             "type": "object",
+            "description": "Передаются только изменяемые поля.",
             "properties": {
-                "id": {
+                "name": {
                     "type": "string",
-                    "format": "uuid",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                    "example": "Иван"
                 },
-                "user_id": {
+                "surname": {
                     "type": "string",
-                    "format": "uuid",
-                    "example": "550e8400-e29b-41d4-a716-446655440001"
+                    "example": "Иванов"
                 },
-                "task_id": {
+                "patronymic": {
                     "type": "string",
-                    "format": "uuid",
-                    "example": "550e8400-e29b-41d4-a716-446655440002"
+                    "example": "Иванович"
                 },
-                "created_at": {
+                "address": {
                     "type": "string",
-                    "format": "date-time",
-                    "example": "2024-07-01T10:00:00Z"
+                    "example": "г. Москва, ул. Ленина, д. 1"
                 },
-                "updated_at": {
+                "passportSerie": {
                     "type": "string",
-                    "format": "date-time",
-                    "example": "2024-07-05T15:30:00Z"
+                    "example": "1234"
                 },
-                "deleted_at": {
+                "passportNumber": {
                     "type": "string",
-                    "format": "date-time",
-                    "example": "2024-07-05T15:30:00Z"
+                    "example": "567890"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": ["admin", "manager", "employee"],
+                    "example": "manager"
+                },
+                "managerID": {
+                    "type": "string",
+                    "format": "uuid",
+                    "x-nullable": true,
+                    "description": "Новый руководитель пользователя. null снимает руководителя, без поля руководитель не меняется.",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
                 }
             }
         },
-        "UserFilter": {
+        "LoginInput": {
             "type": "object",
             "properties": {
-                "field": {
-                    "type": "string",
-                    "example": "Name"
-                },
-                "value": {
+                "user_id": {
                     "type": "string",
-                    "example": "С"
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
-                "operator": {
+                "password": {
                     "type": "string",
-                    "example": "startsWith"
+                    "example": "s3cret-passw0rd"
                 }
-            }
+            },
+            "required": [
+                "user_id",
+                "password"
+            ]
         },
-        "UserFilters": {
+        "UsersPasswordInput": {
             "type": "object",
             "properties": {
-                "filters": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/UserFilter"
-                    }
+                "password": {
+                    "type": "string",
+                    "example": "s3cret-passw0rd"
                 }
-            }
+            },
+            "required": [
+                "password"
+            ]
         },
-        "UserGetListInput": {
+        "APIKey": {
             "type": "object",
             "properties": {
-				"page": {
-                    "type": "integer",
-                    "example": 1
+                "id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
-                "limit": {
-                    "type": "integer",
-                    "example": 3
+                "name": {
+                    "type": "string",
+                    "example": "Расчет зарплаты"
                 },
-                "filters": {
-                    "$ref": "#/definitions/UserFilters",
-					"example": {"field": "Name", "value": "С", "operator": "startsWith"}
+                "prefix": {
+                    "type": "string",
+                    "description": "Начало ключа, чтобы его можно было узнать в списке.",
+                    "example": "emk_Qm9vdH"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "reports:read",
+                            "tasks:write"
+                        ]
+                    },
+                    "example": [
+                        "reports:read"
+                    ]
+                },
+                "createdBy": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "lastUsedAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-07-01T12:00:00Z"
+                },
+                "revokedAt": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-07-01T09:00:00Z"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-07-01T09:00:00Z"
                 }
             }
         },
-        "Period": {
+        "APIKeysCreateInput": {
             "type": "object",
             "properties": {
-                "start_time": {
+                "name": {
                     "type": "string",
-                    "format": "date-time",
-                    "example": "2024-07-03T00:00:00Z"
+                    "example": "Расчет зарплаты"
                 },
-                "end_time": {
-                    "type": "string",
-                    "format": "date-time",
-                    "example":"2024-07-04T23:59:59Z"
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "reports:read",
+                            "tasks:write"
+                        ]
+                    },
+                    "example": [
+                        "reports:read"
+                    ]
                 }
-            }
+            },
+            "required": [
+                "name",
+                "scopes"
+            ]
         }
     }
 }`
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
-	Version:          "",
-	Host:             "",
-	BasePath:         "",
-	Schemes:          []string{},
-	Title:            "",
-	Description:      "",
+	Version:          "1.0.0",
+	Host:             "localhost:8080",
+	BasePath:         "/",
+	Schemes:          []string{"http"},
+	Title:            "Task Management API",
+	Description:      "API для управления пользователями и задачами",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	httpSwagger "github.com/swaggo/http-swagger"
+	"gorm.io/gorm"
+	"net/http"
+	_ "test/docs"
+	"test/internal/auth"
+	"test/internal/clock"
+	"test/internal/handlers/authentication"
+	"test/internal/handlers/crud/apikeys"
+	"test/internal/handlers/crud/tasks"
+	"test/internal/handlers/crud/users"
+	"test/internal/handlers/reports"
+	"test/internal/logging"
+	"test/internal/models"
+	"test/internal/peopleinfo"
+	"test/internal/pii"
+)
+
+// App - зависимости сервиса. main собирает его из настоящих БД, логгера и часов,
+// тесты могут собрать его из подменных и поднять несколько экземпляров в одном процессе
+type App struct {
+	DB         *gorm.DB
+	Log        logrus.FieldLogger
+	Clock      clock.Clock
+	AuthConfig auth.Config
+	// Шифр персональных данных. Должен быть подключен к DB через Cipher.Attach
+	Cipher *pii.Cipher
+	// Может быть nil, тогда создание пользователя только по паспорту недоступно
+	PeopleInfo *peopleinfo.Client
+}
+
+// Router создает обработчики с зависимостями App и регистрирует маршруты с их правами доступа
+func (a *App) Router() http.Handler {
+	authenticator := &auth.Authenticator{DB: a.DB, Log: a.Log, Clock: a.Clock, Config: a.AuthConfig}
+	authHandler := &authentication.AuthHandler{DB: a.DB, Log: a.Log, Auth: authenticator}
+	userHandler := &users.UserHandler{DB: a.DB, Log: a.Log, Clock: a.Clock, Cipher: a.Cipher, PeopleInfo: a.PeopleInfo}
+	taskHandler := &tasks.TaskHandler{DB: a.DB, Log: a.Log, Clock: a.Clock}
+	reportHandler := &reports.ReportHandler{DB: a.DB, Log: a.Log, Clock: a.Clock}
+	apiKeyHandler := &apikeys.APIKeyHandler{DB: a.DB, Log: a.Log, Clock: a.Clock}
+
+	router := mux.NewRouter()
+	router.Use(logging.Middleware(a.Log))
+
+	authRouter := router.PathPrefix("/auth").Subrouter()
+	authRouter.HandleFunc("/login", authHandler.Login).Methods("POST")
+
+	// Права доступа к маршрутам
+	admin := auth.Roles(models.RoleAdmin)
+	managers := auth.Roles(models.RoleAdmin, models.RoleManager)
+	userAccess := auth.Any(admin, auth.Self("user_id"), authenticator.TeamOf("user_id"))
+	taskAccess := auth.Any(admin, authenticator.TaskAssignee("id"), authenticator.TaskTeam("id"))
+	taskManage := auth.Any(admin, authenticator.TaskTeam("id"))
+	timerAccess := auth.All(taskAccess, auth.OnBehalf("user_id", auth.Any(admin, authenticator.QueryTeamOf("user_id"))))
+	reportsRead := auth.Scope(models.ScopeReportsRead)
+	tasksWrite := auth.Scope(models.ScopeTasksWrite)
+
+	usersRouter := router.PathPrefix("/users").Subrouter()
+	usersRouter.Use(authenticator.Middleware)
+	usersRouter.Handle("/create", authenticator.Authorize(admin, userHandler.CreateUser)).Methods("POST")
+	usersRouter.Handle("/delete/{id}", authenticator.Authorize(admin, userHandler.DeleteUserByID)).Methods("DELETE")
+	usersRouter.Handle("/restore/{id}", authenticator.Authorize(admin, userHandler.RestoreUserByID)).Methods("POST")
+	usersRouter.Handle("/update/{id}", authenticator.Authorize(admin, userHandler.UpdateUserByID)).Methods("PUT")
+	usersRouter.Handle("/{id}", authenticator.Authorize(admin, userHandler.PatchUserByID)).Methods("PATCH")
+	usersRouter.Handle("/password/{id}", authenticator.Authorize(auth.Any(admin, auth.Self("id")), userHandler.SetPassword)).Methods("POST")
+	usersRouter.Handle("/get/{id}", authenticator.Authorize(auth.All(
+		auth.Any(admin, auth.Self("id"), authenticator.TeamOf("id")),
+		auth.RevealPassport(models.RoleAdmin),
+	), userHandler.GetUserByID)).Methods("GET")
+	usersRouter.Handle("/list", authenticator.Authorize(auth.All(managers, auth.RevealPassport(models.RoleAdmin)), userHandler.GetUsers)).Methods("POST")
+	usersRouter.Handle("/laborCost/{user_id}", authenticator.Authorize(auth.Any(userAccess, reportsRead), userHandler.LaborCost)).Methods("POST")
+	usersRouter.Handle("/{user_id}/tasks", authenticator.Authorize(userAccess, userHandler.GetUserTasks)).Methods("GET")
+
+	tasksRouter := router.PathPrefix("/tasks").Subrouter()
+	tasksRouter.Use(authenticator.Middleware)
+	tasksRouter.Handle("/create/{user_id}", authenticator.Authorize(auth.Any(userAccess, tasksWrite), taskHandler.CreateTask)).Methods("POST")
+	tasksRouter.Handle("/start/{id}", authenticator.Authorize(timerAccess, taskHandler.StartTaskTimer)).Methods("POST")
+	tasksRouter.Handle("/stop/{id}", authenticator.Authorize(taskAccess, taskHandler.StopTaskTimer)).Methods("POST")
+	tasksRouter.Handle("/pause/{id}", authenticator.Authorize(taskAccess, taskHandler.PauseTaskTimer)).Methods("POST")
+	tasksRouter.Handle("/resume/{id}", authenticator.Authorize(timerAccess, taskHandler.ResumeTaskTimer)).Methods("POST")
+	tasksRouter.Handle("/cancel/{id}", authenticator.Authorize(taskManage, taskHandler.CancelTask)).Methods("POST")
+	tasksRouter.Handle("/history/{id}", authenticator.Authorize(taskAccess, taskHandler.GetTaskHistory)).Methods("GET")
+	tasksRouter.Handle("/get/{id}", authenticator.Authorize(taskAccess, taskHandler.GetTaskByID)).Methods("GET")
+	tasksRouter.Handle("/list", authenticator.Authorize(auth.Authenticated(), taskHandler.GetTasks)).Methods("POST")
+	tasksRouter.Handle("/update/{id}", authenticator.Authorize(taskAccess, taskHandler.UpdateTaskByID)).Methods("PUT")
+	tasksRouter.Handle("/delete/{id}", authenticator.Authorize(taskManage, taskHandler.DeleteTaskByID)).Methods("DELETE")
+	tasksRouter.Handle("/assign/{id}", authenticator.Authorize(taskManage, taskHandler.AssignUser)).Methods("POST")
+	tasksRouter.Handle("/unassign/{id}/{user_id}", authenticator.Authorize(taskManage, taskHandler.UnassignUser)).Methods("DELETE")
+	tasksRouter.Handle("/transfer/{id}", authenticator.Authorize(taskManage, taskHandler.TransferTask)).Methods("POST")
+
+	reportsRouter := router.PathPrefix("/reports").Subrouter()
+	reportsRouter.Use(authenticator.Middleware)
+	reportsRouter.Handle("/laborCost", authenticator.Authorize(auth.Any(managers, reportsRead), reportHandler.LaborCost)).Methods("POST")
+
+	apiKeysRouter := router.PathPrefix("/apiKeys").Subrouter()
+	apiKeysRouter.Use(authenticator.Middleware)
+	apiKeysRouter.Handle("/create", authenticator.Authorize(admin, apiKeyHandler.CreateAPIKey)).Methods("POST")
+	apiKeysRouter.Handle("/list", authenticator.Authorize(admin, apiKeyHandler.GetAPIKeys)).Methods("GET")
+	apiKeysRouter.Handle("/revoke/{id}", authenticator.Authorize(admin, apiKeyHandler.RevokeAPIKey)).Methods("POST")
+
+	router.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
+
+	a.Log.Info("Создан роутинг")
+
+	return router
+}
@@ -0,0 +1,166 @@
+package app
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"test/internal/auth"
+	"test/internal/models"
+	"test/internal/pii"
+	"testing"
+	"time"
+)
+
+const bootstrapSecret = "bootstrap-secret-for-tests"
+
+type fixedClock struct{ now time.Time }
+
+func (c fixedClock) Now() time.Time { return c.now }
+
+// newTestApp собирает App без настоящей БД: gorm в режиме DryRun строит запросы, но не выполняет их,
+// поэтому выборки пусты, а подсчеты равны нулю
+func newTestApp(t *testing.T, keyByte byte) *App {
+	t.Helper()
+
+	database, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost port=1 user=test dbname=test"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("не удалось создать БД: %v", err)
+	}
+
+	cipher, err := pii.NewCipher(bytes.Repeat([]byte{keyByte}, 32), bytes.Repeat([]byte{keyByte + 1}, 32))
+	if err != nil {
+		t.Fatalf("не удалось создать шифр: %v", err)
+	}
+	if err = cipher.Attach(database); err != nil {
+		t.Fatalf("не удалось подключить шифр: %v", err)
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	return &App{
+		DB:    database,
+		Log:   log,
+		Clock: fixedClock{time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)},
+		AuthConfig: auth.Config{
+			Secret:          bytes.Repeat([]byte{keyByte}, 32),
+			TokenTTL:        time.Hour,
+			BootstrapSecret: bootstrapSecret,
+		},
+		Cipher: cipher,
+	}
+}
+
+func TestRouterAuthentication(t *testing.T) {
+	router := newTestApp(t, 1).Router()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header map[string]string
+		body   string
+		want   int
+	}{
+		{name: "без токена", method: http.MethodPost, path: "/users/list", want: http.StatusUnauthorized},
+		{name: "поддельный токен", method: http.MethodPost, path: "/users/list", header: map[string]string{"Authorization": "Bearer abc"}, want: http.StatusUnauthorized},
+		{name: "секрет начальной настройки", method: http.MethodGet, path: "/apiKeys/list", header: map[string]string{"Authorization": "Bearer " + bootstrapSecret}, want: http.StatusOK},
+		{name: "неизвестный пользователь", method: http.MethodPost, path: "/auth/login", body: `{"user_id":"` + uuid.NewString() + `","password":"password1"}`, want: http.StatusUnauthorized},
+		{name: "задача без названия", method: http.MethodPost, path: "/tasks/create/" + uuid.NewString(), header: map[string]string{"Authorization": "Bearer " + bootstrapSecret}, body: `{"name":"  ","description":"Описание"}`, want: http.StatusBadRequest},
+		{name: "задача с названием", method: http.MethodPost, path: "/tasks/create/" + uuid.NewString(), header: map[string]string{"Authorization": "Bearer " + bootstrapSecret}, body: `{"name":"Задача","hours":100}`, want: http.StatusOK},
+		{name: "некорректный ID задачи", method: http.MethodGet, path: "/tasks/get/abc", header: map[string]string{"Authorization": "Bearer " + bootstrapSecret}, want: http.StatusBadRequest},
+		{name: "удаление по некорректному ID задачи", method: http.MethodDelete, path: "/tasks/delete/abc", header: map[string]string{"Authorization": "Bearer " + bootstrapSecret}, want: http.StatusBadRequest},
+		{name: "документация без авторизации", method: http.MethodGet, path: "/swagger/doc.json", want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			for key, value := range tt.header {
+				request.Header.Set(key, value)
+			}
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.want {
+				t.Fatalf("статус %d, ожидался %d: %s", recorder.Code, tt.want, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestTokenUsesAppClock(t *testing.T) {
+	app := newTestApp(t, 1)
+	issuer := &auth.Authenticator{Clock: app.Clock, Config: app.AuthConfig}
+
+	token, expiresAt, err := issuer.IssueToken(uuid.New())
+	if err != nil {
+		t.Fatalf("не удалось выпустить токен: %v", err)
+	}
+	if want := app.Clock.Now().Add(time.Hour); !expiresAt.Equal(want) {
+		t.Fatalf("срок действия %v, ожидался %v", expiresAt, want)
+	}
+
+	later := &auth.Authenticator{Clock: fixedClock{expiresAt.Add(time.Minute)}, Config: app.AuthConfig}
+	if _, err = later.ParseToken(token); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("просроченный токен принят: %v", err)
+	}
+
+	other := newTestApp(t, 3)
+	foreign := &auth.Authenticator{Clock: app.Clock, Config: other.AuthConfig}
+	if _, err = foreign.ParseToken(token); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("токен с чужим секретом принят: %v", err)
+	}
+}
+
+// Два экземпляра в одном процессе шифруют паспорта своими ключами
+func TestAppsEncryptWithOwnKeys(t *testing.T) {
+	first, second := newTestApp(t, 1), newTestApp(t, 3)
+
+	stored := func(app *App) string {
+		user := models.Users{ID: uuid.New(), PassportSerie: "1234", PassportNumber: "567890"}
+		statement := app.DB.Create(&user).Statement
+
+		for _, v := range statement.Vars {
+			valuer, ok := v.(driver.Valuer)
+			if !ok {
+				continue
+			}
+			value, err := valuer.Value()
+			if err != nil {
+				t.Fatalf("не удалось получить значение: %v", err)
+			}
+			if s, ok := value.(string); ok && strings.HasPrefix(s, "enc:") {
+				return s
+			}
+		}
+		t.Fatal("паспорт не зашифрован")
+		return ""
+	}
+
+	encrypted := stored(first)
+	if plain, err := first.Cipher.Decrypt(encrypted); err != nil || (plain != "1234" && plain != "567890") {
+		t.Fatalf("свой ключ не расшифровал значение: %q, %v", plain, err)
+	}
+	if _, err := second.Cipher.Decrypt(encrypted); err == nil {
+		t.Fatal("чужой ключ расшифровал значение")
+	}
+	if _, err := second.Cipher.Decrypt(stored(second)); err != nil {
+		t.Fatalf("второй экземпляр не расшифровал свое значение: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"test/internal/models"
+)
+
+const (
+	apiKeyHeader = "X-API-Key"
+	apiKeyPrefix = "emk_"
+)
+
+var ErrInvalidAPIKey = errors.New("Недействительный ключ API")
+
+// GenerateAPIKey создает новый ключ. Вызывающему он отдается один раз, в БД сохраняется только хеш
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	secret := make([]byte, 32)
+	if _, err = rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:len(apiKeyPrefix)+6], HashAPIKey(key), nil
+}
+
+// HashAPIKey - у ключа достаточно энтропии, поэтому хватает SHA-256 без соли, и по хешу можно искать
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (a *Authenticator) authenticateAPIKey(key string) (Identity, error) {
+	var apiKey models.APIKeys
+	err := a.DB.Where("key_hash = ? AND revoked_at IS NULL", HashAPIKey(key)).First(&apiKey).Error
+	if err != nil {
+		a.Log.Errorf("Ключ API не найден: %v", err)
+		return Identity{}, ErrInvalidAPIKey
+	}
+
+	// UpdateColumn не трогает updated_at, чтобы время изменения ключа отражало только его настройку
+	if err = a.DB.Model(&apiKey).UpdateColumn("last_used_at", a.Clock.Now()).Error; err != nil {
+		a.Log.Warnf("Не удалось обновить время использования ключа API %v: %v", apiKey.ID, err)
+	}
+
+	a.Log.Debugf("Запрос выполнен с ключом API %v (%s)", apiKey.ID, apiKey.Name)
+
+	return Identity{APIKey: &apiKey}, nil
+}
+
+func apiKeyFromHeader(r *http.Request) (string, bool) {
+	key := r.Header.Get(apiKeyHeader)
+	return key, key != ""
+}
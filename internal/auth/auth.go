@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"os"
+	"test/internal/clock"
+	"time"
+)
+
+var (
+	ErrInvalidToken       = errors.New("Недействительный токен")
+	ErrInvalidCredentials = errors.New("Неверный ID пользователя или пароль")
+	ErrPasswordTooShort   = errors.New("Пароль должен быть не короче 8 символов")
+	ErrPasswordTooLong    = errors.New("Пароль должен быть не длиннее 72 байт")
+)
+
+// Config - настройки выдачи и проверки токенов
+type Config struct {
+	Secret   []byte
+	TokenTTL time.Duration
+	// Секрет для начальной настройки, пока ни у одного пользователя нет пароля
+	BootstrapSecret string
+}
+
+// LoadConfig читает настройки из окружения: JWT_SECRET (не короче 32 символов), JWT_TTL (например 24h)
+// и AUTH_BOOTSTRAP_SECRET
+func LoadConfig() (Config, error) {
+	config := Config{
+		Secret:          []byte(os.Getenv("JWT_SECRET")),
+		TokenTTL:        24 * time.Hour,
+		BootstrapSecret: os.Getenv("AUTH_BOOTSTRAP_SECRET"),
+	}
+	if len(config.Secret) < 32 {
+		return Config{}, errors.New("JWT_SECRET должен быть задан и быть не короче 32 символов")
+	}
+
+	if value := os.Getenv("JWT_TTL"); value != "" {
+		var err error
+		if config.TokenTTL, err = time.ParseDuration(value); err != nil {
+			return Config{}, fmt.Errorf("Некорректное значение JWT_TTL: %w", err)
+		}
+	}
+
+	return config, nil
+}
+
+// Authenticator выдает токены, определяет вызывающего и проверяет его права
+type Authenticator struct {
+	DB     *gorm.DB
+	Log    logrus.FieldLogger
+	Clock  clock.Clock
+	Config Config
+}
+
+// IssueToken выдает подписанный токен для пользователя
+func (a *Authenticator) IssueToken(userID uuid.UUID) (string, time.Time, error) {
+	now := a.Clock.Now()
+	expiresAt := now.Add(a.Config.TokenTTL)
+	claims := jwt.RegisteredClaims{
+		Subject:   userID.String(),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(a.Config.Secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// ParseToken проверяет подпись и срок действия токена и возвращает ID пользователя
+func (a *Authenticator) ParseToken(token string) (uuid.UUID, error) {
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return a.Config.Secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired(), jwt.WithTimeFunc(a.Clock.Now))
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+	return userID, nil
+}
+
+// HashPassword возвращает bcrypt-хеш пароля
+func HashPassword(password string) (string, error) {
+	if len([]rune(password)) < 8 {
+		return "", ErrPasswordTooShort
+	}
+	if len(password) > 72 {
+		return "", ErrPasswordTooLong
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword сравнивает пароль с хешем. Пустой хеш означает, что пароль не задан
+func CheckPassword(hash, password string) bool {
+	if hash == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+	"test/internal/models"
+)
+
+// Identity - тот, кто выполняет запрос
+type Identity struct {
+	UserID uuid.UUID
+	Role   models.UserRole
+	// Запрос выполнен с AUTH_BOOTSTRAP_SECRET, пользователя за ним нет. Права как у администратора
+	Bootstrap bool
+	// Запрос выполнен с ключом API, пользователя за ним нет. Права определяются областями ключа
+	APIKey *models.APIKeys
+}
+
+type contextKey struct{}
+
+// WithIdentity кладет вызывающего в контекст запроса
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, identity)
+}
+
+// FromContext возвращает вызывающего, если запрос прошел через Middleware
+func FromContext(ctx context.Context) (Identity, bool) {
+	if ctx == nil {
+		return Identity{}, false
+	}
+	identity, ok := ctx.Value(contextKey{}).(Identity)
+	return identity, ok
+}
+
+// Middleware пропускает только запросы с действительным токеном в заголовке Authorization: Bearer
+// или ключом API в заголовке X-API-Key. Удаленный пользователь теряет доступ сразу, не дожидаясь истечения токена
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var identity Identity
+		var err error
+		if key, ok := apiKeyFromHeader(r); ok {
+			identity, err = a.authenticateAPIKey(key)
+		} else if token, ok := bearerToken(r); ok {
+			identity, err = a.authenticate(token)
+		} else {
+			a.unauthorized(w, "Требуется авторизация")
+			return
+		}
+		if err != nil {
+			a.unauthorized(w, err.Error())
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), identity)))
+	})
+}
+
+func (a *Authenticator) authenticate(token string) (Identity, error) {
+	if a.Config.BootstrapSecret != "" && subtle.ConstantTimeCompare([]byte(token), []byte(a.Config.BootstrapSecret)) == 1 {
+		open, err := a.BootstrapOpen()
+		if err != nil {
+			return Identity{}, err
+		}
+		if !open {
+			return Identity{}, errors.New("Начальная настройка завершена, войдите под пользователем")
+		}
+		a.Log.Warn("Запрос выполнен с секретом начальной настройки")
+		return Identity{Bootstrap: true}, nil
+	}
+
+	userID, err := a.ParseToken(token)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	// Роль читается при каждом запросе, чтобы её изменение действовало сразу
+	var user models.Users
+	if err = a.DB.Select("id", "role").First(&user, "id = ?", userID).Error; err != nil {
+		a.Log.Errorf("Пользователь из токена не найден: %v", err)
+		return Identity{}, ErrInvalidToken
+	}
+
+	return Identity{UserID: user.ID, Role: user.Role}, nil
+}
+
+// BootstrapOpen сообщает, что ни у одного пользователя еще нет пароля.
+// Удаленные пользователи тоже учитываются, иначе удаление всех пользователей с паролем снова открыло бы начальную настройку
+func (a *Authenticator) BootstrapOpen() (bool, error) {
+	var count int64
+	err := a.DB.Unscoped().Model(&models.Users{}).Where("password_hash <> ''").Count(&count).Error
+	return count == 0, err
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	token = strings.TrimSpace(token)
+	return token, ok && token != ""
+}
+
+func (a *Authenticator) unauthorized(w http.ResponseWriter, message string) {
+	a.Log.Errorf("Запрос отклонен: %s", message)
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	http.Error(w, message, 401)
+}
@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"net/http"
+	"test/internal/models"
+)
+
+var ErrForbidden = errors.New("Недостаточно прав для выполнения запроса")
+
+// Policy решает, можно ли вызывающему выполнить запрос. Политики назначаются маршрутам в app.Router
+type Policy func(r *http.Request, identity Identity) (bool, error)
+
+// Authorize пропускает запрос к обработчику, только если его разрешает политика.
+// Вызов при начальной настройке разрешен всегда
+func (a *Authenticator) Authorize(policy Policy, handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := FromContext(r.Context())
+		if !ok {
+			a.unauthorized(w, "Требуется авторизация")
+			return
+		}
+
+		if !identity.Bootstrap {
+			allowed, err := policy(r, identity)
+			if err != nil {
+				a.Log.Errorf("Не удалось проверить права: %v", err)
+				http.Error(w, "Не удалось проверить права", 500)
+				return
+			}
+			if !allowed {
+				a.Log.Errorf("Пользователю %v (%s) отказано в доступе к %s %s", identity.UserID, identity.Role, r.Method, r.URL.Path)
+				http.Error(w, ErrForbidden.Error(), 403)
+				return
+			}
+		}
+
+		handler(w, r)
+	})
+}
+
+// Any разрешает запрос, если его разрешает хотя бы одна из политик
+func Any(policies ...Policy) Policy {
+	return func(r *http.Request, identity Identity) (bool, error) {
+		for _, policy := range policies {
+			allowed, err := policy(r, identity)
+			if err != nil || allowed {
+				return allowed, err
+			}
+		}
+		return false, nil
+	}
+}
+
+// All разрешает запрос, только если его разрешают все политики
+func All(policies ...Policy) Policy {
+	return func(r *http.Request, identity Identity) (bool, error) {
+		for _, policy := range policies {
+			allowed, err := policy(r, identity)
+			if err != nil || !allowed {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+}
+
+// Authenticated разрешает запрос любому авторизованному пользователю, но не ключу API
+func Authenticated() Policy {
+	return func(r *http.Request, identity Identity) (bool, error) {
+		return identity.UserID != uuid.Nil, nil
+	}
+}
+
+// Scope разрешает запрос ключу API с указанной областью
+func Scope(scope models.APIKeyScope) Policy {
+	return func(r *http.Request, identity Identity) (bool, error) {
+		return identity.APIKey != nil && identity.APIKey.HasScope(scope), nil
+	}
+}
+
+// Roles разрешает запрос пользователям с одной из ролей
+func Roles(roles ...models.UserRole) Policy {
+	return func(r *http.Request, identity Identity) (bool, error) {
+		for _, role := range roles {
+			if identity.Role == role {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// Self разрешает запрос, если параметр маршрута param - ID самого вызывающего
+func Self(param string) Policy {
+	return func(r *http.Request, identity Identity) (bool, error) {
+		userID, err := uuid.Parse(mux.Vars(r)[param])
+		return err == nil && identity.UserID != uuid.Nil && userID == identity.UserID, nil
+	}
+}
+
+// TeamOf разрешает запрос руководителю пользователя из параметра маршрута param
+func (a *Authenticator) TeamOf(param string) Policy {
+	return a.teamOf(func(r *http.Request) string { return mux.Vars(r)[param] })
+}
+
+// QueryTeamOf разрешает запрос руководителю пользователя из параметра запроса param
+func (a *Authenticator) QueryTeamOf(param string) Policy {
+	return a.teamOf(func(r *http.Request) string { return r.URL.Query().Get(param) })
+}
+
+func (a *Authenticator) teamOf(requested func(r *http.Request) string) Policy {
+	return func(r *http.Request, identity Identity) (bool, error) {
+		if identity.Role != models.RoleManager {
+			return false, nil
+		}
+		userID, err := uuid.Parse(requested(r))
+		if err != nil {
+			return false, nil
+		}
+
+		var count int64
+		err = a.DB.Model(&models.Users{}).
+			Where("id = ? AND manager_id = ?", userID, identity.UserID).
+			Count(&count).Error
+		return count > 0, err
+	}
+}
+
+// TaskAssignee разрешает запрос исполнителю задачи из параметра маршрута param
+func (a *Authenticator) TaskAssignee(param string) Policy {
+	return func(r *http.Request, identity Identity) (bool, error) {
+		var count int64
+		err := a.DB.Model(&models.UsersTasks{}).
+			Where("task_id = ? AND user_id = ?", mux.Vars(r)[param], identity.UserID).
+			Count(&count).Error
+		return count > 0, err
+	}
+}
+
+// TaskTeam разрешает запрос руководителю хотя бы одного из исполнителей задачи
+func (a *Authenticator) TaskTeam(param string) Policy {
+	return func(r *http.Request, identity Identity) (bool, error) {
+		if identity.Role != models.RoleManager {
+			return false, nil
+		}
+
+		var count int64
+		err := a.DB.Model(&models.UsersTasks{}).
+			Joins("JOIN users ON users.id::text = users_tasks.user_id AND users.deleted_at IS NULL").
+			Where("users_tasks.task_id = ? AND users.manager_id = ?", mux.Vars(r)[param], identity.UserID).
+			Count(&count).Error
+		return count > 0, err
+	}
+}
+
+// OnBehalf разрешает указать в параметре запроса param другого пользователя, только если это разрешает policy.
+// Без параметра или с ID самого вызывающего запрос разрешен, некорректный ID отклоняет обработчик
+func OnBehalf(param string, policy Policy) Policy {
+	return func(r *http.Request, identity Identity) (bool, error) {
+		userID, err := uuid.Parse(r.URL.Query().Get(param))
+		if err != nil || userID == identity.UserID {
+			return true, nil
+		}
+		return policy(r, identity)
+	}
+}
+
+// RevealPassport пропускает запросы без ?reveal=true, а раскрытие паспортных данных разрешает только ролям roles
+func RevealPassport(roles ...models.UserRole) Policy {
+	return func(r *http.Request, identity Identity) (bool, error) {
+		if !PassportRevealRequested(r) {
+			return true, nil
+		}
+		return Roles(roles...)(r, identity)
+	}
+}
+
+// PassportRevealRequested - клиент просит отдать паспортные данные без маски
+func PassportRevealRequested(r *http.Request) bool {
+	return r.URL.Query().Get("reveal") == "true"
+}
+
+// ScopeUsers ограничивает выборку пользователей тем, кого видит вызывающий:
+// администратор и ключ API - всех, руководитель - себя и свою команду, сотрудник - только себя
+func ScopeUsers(query *gorm.DB, identity Identity) *gorm.DB {
+	if identity.Bootstrap || identity.APIKey != nil || identity.Role == models.RoleAdmin {
+		return query
+	}
+	if identity.Role == models.RoleManager {
+		return query.Where("users.id = ? OR users.manager_id = ?", identity.UserID, identity.UserID)
+	}
+	return query.Where("users.id = ?", identity.UserID)
+}
+
+// ActingUser возвращает пользователя, за которого выполняется действие: явно запрошенного, если его
+// разрешила OnBehalf, иначе самого вызывающего. Администратор, руководитель и начальная настройка без
+// явного пользователя получают uuid.Nil - исполнителя тогда определяет задача
+func ActingUser(identity Identity, requested uuid.UUID) uuid.UUID {
+	if requested != uuid.Nil || identity.Bootstrap || identity.Role == models.RoleAdmin || identity.Role == models.RoleManager {
+		return requested
+	}
+	return identity.UserID
+}
+
+// ScopeTasks ограничивает выборку задач теми, что назначены пользователям из ScopeUsers
+func ScopeTasks(query *gorm.DB, identity Identity) *gorm.DB {
+	if identity.Bootstrap || identity.APIKey != nil || identity.Role == models.RoleAdmin {
+		return query
+	}
+	users := ScopeUsers(query.Session(&gorm.Session{NewDB: true}).Model(&models.Users{}).Select("users.id::text"), identity)
+	return query.Where("EXISTS (SELECT 1 FROM users_tasks WHERE users_tasks.task_id = tasks.id::text AND users_tasks.deleted_at IS NULL AND users_tasks.user_id IN (?))", users)
+}
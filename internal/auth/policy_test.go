@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"net/http/httptest"
+	"test/internal/models"
+	"testing"
+)
+
+func TestOnBehalf(t *testing.T) {
+	self, other := uuid.New(), uuid.New()
+
+	// В DryRun запросы не выполняются, поэтому other не состоит ни в чьей команде
+	database, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost port=1 user=test dbname=test"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("не удалось создать БД: %v", err)
+	}
+	authenticator := &Authenticator{DB: database}
+	policy := OnBehalf("user_id", Any(Roles(models.RoleAdmin), authenticator.QueryTeamOf("user_id")))
+
+	tests := []struct {
+		name     string
+		query    string
+		identity Identity
+		want     bool
+	}{
+		{name: "без параметра", identity: Identity{UserID: self, Role: models.RoleEmployee}, want: true},
+		{name: "сотрудник за себя", query: self.String(), identity: Identity{UserID: self, Role: models.RoleEmployee}, want: true},
+		{name: "сотрудник за другого", query: other.String(), identity: Identity{UserID: self, Role: models.RoleEmployee}, want: false},
+		{name: "руководитель за исполнителя не из своей команды", query: other.String(), identity: Identity{UserID: self, Role: models.RoleManager}, want: false},
+		{name: "администратор за другого", query: other.String(), identity: Identity{UserID: self, Role: models.RoleAdmin}, want: true},
+		{name: "некорректный ID отклоняет обработчик", query: "abc", identity: Identity{UserID: self, Role: models.RoleEmployee}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest("POST", "/tasks/start/1?user_id="+tt.query, nil)
+			allowed, err := policy(request, tt.identity)
+			if err != nil || allowed != tt.want {
+				t.Fatalf("OnBehalf = %v, %v, ожидалось %v", allowed, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestActingUser(t *testing.T) {
+	self, other := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name      string
+		identity  Identity
+		requested uuid.UUID
+		want      uuid.UUID
+	}{
+		{name: "сотрудник без параметра", identity: Identity{UserID: self, Role: models.RoleEmployee}, want: self},
+		{name: "руководитель без параметра", identity: Identity{UserID: self, Role: models.RoleManager}, want: uuid.Nil},
+		{name: "начальная настройка", identity: Identity{Bootstrap: true}, want: uuid.Nil},
+		{name: "явный пользователь", identity: Identity{UserID: self, Role: models.RoleAdmin}, requested: other, want: other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ActingUser(tt.identity, tt.requested); got != tt.want {
+				t.Fatalf("ActingUser = %v, ожидалось %v", got, tt.want)
+			}
+		})
+	}
+}
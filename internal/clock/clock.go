@@ -0,0 +1,15 @@
+package clock
+
+import "time"
+
+// Clock - источник текущего времени. В тестах подменяется на фиксированное время
+type Clock interface {
+	Now() time.Time
+}
+
+// System возвращает системное время
+type System struct{}
+
+func (System) Now() time.Time {
+	return time.Now()
+}
@@ -2,60 +2,229 @@ package db
 
 import (
 	"fmt"
-	"github.com/joho/godotenv"
+	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"os"
-	"test/internal/logging"
 	"test/internal/models"
+	"test/internal/pii"
+	"time"
 )
 
-var PostgresClient *gorm.DB
-
-func ConnectDB() {
-	logging.Log.Info("Начало подключение к БД")
-
-	err := godotenv.Load("./.env")
-	if err != nil {
-		logging.Log.WithFields(logrus.Fields{
-			"error": err,
-		}).Fatal("Не удалось загрузить .env файл")
-	}
-
-	logging.Log.Debug("Загружен .env файл")
+// ConnectDB подключается к БД по настройкам из окружения, подключает к соединению шифр персональных данных
+// и проводит миграции. При миграции шифруются паспортные данные, записанные открытым текстом
+func ConnectDB(log logrus.FieldLogger, cipher *pii.Cipher) (*gorm.DB, error) {
+	log.Info("Начало подключение к БД")
 
 	username := os.Getenv("POSTGRES_USER")
 	password := os.Getenv("POSTGRES_PASSWORD")
 	dbName := os.Getenv("POSTGRES_DB")
 
-	logging.Log.Debugf("Параметры подключения: username=%s, dbName=%s", username, dbName)
+	log.Debugf("Параметры подключения: username=%s, dbName=%s", username, dbName)
 
 	connStr := fmt.Sprintf("postgres://%s:%s@localhost:5432/%s?sslmode=disable", username, password, dbName)
 
-	PostgresClient, err = gorm.Open(postgres.Open(connStr), &gorm.Config{})
+	// TranslateError превращает ошибки Postgres в ошибки gorm, например нарушение уникальности в gorm.ErrDuplicatedKey
+	client, err := gorm.Open(postgres.Open(connStr), &gorm.Config{TranslateError: true})
 	if err != nil {
-		logging.Log.WithFields(logrus.Fields{
-			"error": err,
-		}).Fatal("Не удалось подключится к БД")
+		return nil, fmt.Errorf("Не удалось подключится к БД: %w", err)
 	}
 
-	logging.Log.Debug("Открыто соединение с БД. Создана переменная PostgresClient для подключения к бд")
+	log.Info("Успешное подключение к БД!")
+
+	if err = cipher.Attach(client); err != nil {
+		return nil, fmt.Errorf("Не удалось подключить шифрование персональных данных: %w", err)
+	}
 
-	logging.Log.Info("Успешное подключение к БД!")
+	if err = migrateModels(client, log, cipher); err != nil {
+		return nil, err
+	}
 
-	migrateModels()
+	return client, nil
 }
 
-func migrateModels() {
-	logging.Log.Info("Начало миграции моделей в БД")
+func migrateModels(client *gorm.DB, log logrus.FieldLogger, cipher *pii.Cipher) error {
+	log.Info("Начало миграции моделей в БД")
+	// Уникальность паспорта теперь обеспечивает слепой индекс, зашифрованный full_passport уникальным быть не может
+	if err := dropFullPassportUnique(client, log); err != nil {
+		return fmt.Errorf("Не удалось снять ограничение уникальности с full_passport: %w", err)
+	}
+
 	// Миграция моделей
-	err := PostgresClient.AutoMigrate(&models.Users{}, &models.Tasks{}, &models.UsersTasks{})
+	err := client.AutoMigrate(&models.Users{}, &models.Tasks{}, &models.UsersTasks{}, &models.TimeEntries{}, &models.TaskStateTransitions{}, &models.APIKeys{})
 	if err != nil {
-		logging.Log.WithFields(logrus.Fields{
-			"error": err,
-		}).Fatal("Не удалось провести миграцию структуры БД")
+		return fmt.Errorf("Не удалось провести миграцию структуры БД: %w", err)
+	}
+
+	if err = migratePassportEncryption(client, log, cipher); err != nil {
+		return fmt.Errorf("Не удалось зашифровать паспортные данные пользователей: %w", err)
+	}
+
+	if err = migrateUsersSearchIndex(client, log); err != nil {
+		return fmt.Errorf("Не удалось создать индекс полнотекстового поиска пользователей: %w", err)
+	}
+
+	if err = migrateOrphanUserLinks(client, log); err != nil {
+		return fmt.Errorf("Не удалось удалить связи задач с удаленными пользователями: %w", err)
+	}
+
+	if err = migrateTaskStates(client, log); err != nil {
+		return fmt.Errorf("Не удалось перенести статусы задач в состояния: %w", err)
+	}
+
+	if err = migrateTaskSessions(client, log); err != nil {
+		return fmt.Errorf("Не удалось перенести интервалы задач в сессии: %w", err)
+	}
+
+	log.Info("Успешная миграция!")
+
+	return nil
+}
+
+func dropFullPassportUnique(client *gorm.DB, log logrus.FieldLogger) error {
+	if !client.Migrator().HasTable(&models.Users{}) {
+		return nil
+	}
+
+	// Имя ограничения зависит от того, какой версией gorm создавалась таблица
+	for _, constraint := range []string{"uni_users_full_passport", "users_full_passport_key"} {
+		if err := client.Exec("ALTER TABLE users DROP CONSTRAINT IF EXISTS " + constraint).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Шифруем паспортные данные, записанные до включения шифрования, и заполняем для них слепой индекс.
+// При ротации ключей перешифровываем данные всех пользователей, включая удаленных, и пересчитываем слепой индекс новым ключом
+func migratePassportEncryption(client *gorm.DB, log logrus.FieldLogger, cipher *pii.Cipher) error {
+	var users []struct {
+		ID             uuid.UUID
+		PassportSerie  string
+		PassportNumber string
+	}
+	query := client.Table("users").Select("id, passport_serie, passport_number")
+	if !cipher.Rotating() {
+		query = query.Where("passport_hash IS NULL OR passport_hash = ''")
+	}
+	if err := query.Find(&users).Error; err != nil {
+		return err
 	}
-	logging.Log.Info("Успешная миграция!")
+
+	if cipher.Rotating() {
+		log.Warnf("Заданы ключи до ротации, паспортные данные перешифровываются: %d пользователей. После запуска удалите PASSPORT_*_PREVIOUS из окружения", len(users))
+	} else {
+		log.Debugf("Пользователей для шифрования паспортных данных: %d", len(users))
+	}
+
+	for _, user := range users {
+		serie, err := cipher.Decrypt(user.PassportSerie)
+		if err != nil {
+			return err
+		}
+		number, err := cipher.Decrypt(user.PassportNumber)
+		if err != nil {
+			return err
+		}
+
+		values := map[string]interface{}{"passport_hash": cipher.BlindIndex(serie + number)}
+		for column, plain := range map[string]string{
+			"passport_serie":  serie,
+			"passport_number": number,
+			"full_passport":   serie + number,
+		} {
+			if values[column], err = cipher.Encrypt(plain); err != nil {
+				return err
+			}
+		}
+
+		if err = client.Table("users").Where("id = ?", user.ID).Updates(values).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Индекс для полнотекстового поиска по пользователям с русской морфологией
+func migrateUsersSearchIndex(client *gorm.DB, log logrus.FieldLogger) error {
+	return client.Exec("CREATE INDEX IF NOT EXISTS idx_users_search ON users USING GIN (" + models.UsersSearchVector + ")").Error
+}
+
+// Раньше пользователи удалялись из БД полностью, а их связи с задачами оставались.
+// Такие связи помечаем удаленными
+func migrateOrphanUserLinks(client *gorm.DB, log logrus.FieldLogger) error {
+	return client.Exec(`UPDATE users_tasks SET deleted_at = NOW()
+		WHERE deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM users WHERE users.id::text = users_tasks.user_id)`).Error
+}
+
+// Заменяем булев статус задачи на состояние и удаляем старую колонку
+func migrateTaskStates(client *gorm.DB, log logrus.FieldLogger) error {
+	if !client.Migrator().HasColumn(&models.Tasks{}, "status") {
+		return nil
+	}
+
+	log.Info("Перенос статусов задач в состояния")
+
+	err := client.Exec(`UPDATE tasks SET state = CASE
+		WHEN status THEN 'in_progress'
+		WHEN start_time IS NULL THEN 'new'
+		WHEN end_time IS NOT NULL AND end_time >= start_time THEN 'done'
+		ELSE 'paused'
+	END`).Error
+	if err != nil {
+		return err
+	}
+
+	return client.Migrator().DropColumn(&models.Tasks{}, "status")
+}
+
+// Переносим интервал StartTime/EndTime задач, у которых ещё нет сессий, в первую сессию
+func migrateTaskSessions(client *gorm.DB, log logrus.FieldLogger) error {
+	var tasks []struct {
+		ID        uuid.UUID
+		State     models.TaskState
+		StartTime *time.Time
+		EndTime   *time.Time
+	}
+	err := client.Table("tasks").
+		Select("id, state, start_time, end_time").
+		Where("start_time IS NOT NULL AND NOT EXISTS (SELECT 1 FROM time_entries WHERE time_entries.task_id = tasks.id::text)").
+		Find(&tasks).Error
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Задач для переноса в сессии: %d", len(tasks))
+
+	for _, task := range tasks {
+		var userTask models.UsersTasks
+		if err = client.Where("task_id = ?", task.ID).Order("created_at").First(&userTask).Error; err != nil {
+			log.Warnf("У задачи %v нет назначенного пользователя, перенос пропущен", task.ID)
+			continue
+		}
+
+		entry := models.TimeEntries{
+			TaskID:    task.ID,
+			UserID:    userTask.UserID,
+			StartTime: *task.StartTime,
+		}
+		// Запущенная задача остаётся с открытой сессией
+		if task.State != models.TaskStateInProgress && task.EndTime != nil {
+			entry.EndTime = task.EndTime
+		}
+
+		entry.ID, err = uuid.NewUUID()
+		if err != nil {
+			return err
+		}
+
+		if err = client.Create(&entry).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,305 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type FieldType int
+
+const (
+	String FieldType = iota
+	UUID
+	Date
+)
+
+const (
+	OperatorEquals     = "equals"
+	OperatorNotEquals  = "notEquals"
+	OperatorContains   = "contains"
+	OperatorStartsWith = "startsWith"
+	OperatorEndsWith   = "endsWith"
+	OperatorIn         = "in"
+	OperatorGt         = "gt"
+	OperatorGte        = "gte"
+	OperatorLt         = "lt"
+	OperatorLte        = "lte"
+	OperatorBetween    = "between"
+	OperatorIsNull     = "isNull"
+)
+
+// Максимальная вложенность групп условий
+const maxDepth = 5
+
+// Операторы, допустимые для каждого типа поля
+var typeOperators = map[FieldType][]string{
+	String: {OperatorEquals, OperatorNotEquals, OperatorContains, OperatorStartsWith, OperatorEndsWith, OperatorIn, OperatorIsNull},
+	UUID:   {OperatorEquals, OperatorNotEquals, OperatorIn, OperatorIsNull},
+	Date:   {OperatorEquals, OperatorNotEquals, OperatorGt, OperatorGte, OperatorLt, OperatorLte, OperatorBetween, OperatorIsNull},
+}
+
+var comparisons = map[string]string{
+	OperatorEquals:    "=",
+	OperatorNotEquals: "<>",
+	OperatorGt:        ">",
+	OperatorGte:       ">=",
+	OperatorLt:        "<",
+	OperatorLte:       "<=",
+}
+
+// Field - публичное поле фильтрации и колонка БД, на которую оно отображается
+type Field struct {
+	Column string
+	Type   FieldType
+}
+
+// Schema - белый список полей, по которым клиент может фильтровать. Ключ - имя поля в JSON
+type Schema map[string]Field
+
+type Condition struct {
+	Field    string
+	Value    string
+	Values   []string
+	Operator string
+	// Сравнение строк без учета регистра
+	CaseInsensitive bool
+}
+
+// Group - условия, объединенные через AND, и вложенные группы.
+// Все группы из And должны выполняться, из Or - хотя бы одна
+type Group struct {
+	Conditions []Condition
+	And        []Group
+	Or         []Group
+}
+
+// Error - ошибка в условии фильтрации, отдается клиенту как 400
+type Error struct {
+	Message  string `json:"error"`
+	Field    string `json:"field,omitempty"`
+	Operator string `json:"operator,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Apply проверяет условия по схеме и добавляет их в запрос.
+// Имена колонок берутся только из схемы, значения передаются параметрами
+func (s Schema) Apply(query *gorm.DB, group Group) (*gorm.DB, error) {
+	sql, args, err := s.build(group, 0)
+	if err != nil {
+		return nil, err
+	}
+	if sql == "" {
+		return query, nil
+	}
+	return query.Where(sql, args...), nil
+}
+
+// Summary перечисляет поля и операторы условий без значений: значения могут содержать персональные данные,
+// а в лог достаточно того, по чему фильтровал клиент
+func (g Group) Summary() []string {
+	summary := []string{}
+	for _, condition := range g.Conditions {
+		summary = append(summary, condition.Field+" "+condition.Operator)
+	}
+	for _, nested := range g.And {
+		summary = append(summary, nested.Summary()...)
+	}
+	for _, nested := range g.Or {
+		summary = append(summary, nested.Summary()...)
+	}
+	return summary
+}
+
+// WriteError отвечает клиенту 400 с описанием ошибки фильтрации
+func WriteError(w http.ResponseWriter, err *Error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+
+	json.NewEncoder(w).Encode(err)
+}
+
+func (s Schema) build(group Group, depth int) (string, []interface{}, error) {
+	if depth > maxDepth {
+		return "", nil, &Error{Message: fmt.Sprintf("Превышена максимальная вложенность групп фильтров: %d", maxDepth)}
+	}
+
+	var parts []string
+	var args []interface{}
+
+	for _, condition := range group.Conditions {
+		sql, conditionArgs, err := s.condition(condition)
+		if err != nil {
+			return "", nil, err
+		}
+		if sql == "" {
+			continue
+		}
+		parts = append(parts, sql)
+		args = append(args, conditionArgs...)
+	}
+
+	for _, nested := range group.And {
+		sql, nestedArgs, err := s.build(nested, depth+1)
+		if err != nil {
+			return "", nil, err
+		}
+		if sql == "" {
+			continue
+		}
+		parts = append(parts, "("+sql+")")
+		args = append(args, nestedArgs...)
+	}
+
+	var alternatives []string
+	for _, nested := range group.Or {
+		sql, nestedArgs, err := s.build(nested, depth+1)
+		if err != nil {
+			return "", nil, err
+		}
+		if sql == "" {
+			continue
+		}
+		alternatives = append(alternatives, "("+sql+")")
+		args = append(args, nestedArgs...)
+	}
+	if len(alternatives) > 0 {
+		parts = append(parts, "("+strings.Join(alternatives, " OR ")+")")
+	}
+
+	return strings.Join(parts, " AND "), args, nil
+}
+
+func (s Schema) condition(condition Condition) (string, []interface{}, error) {
+	field, ok := s.lookup(condition.Field)
+	if !ok {
+		return "", nil, &Error{Message: fmt.Sprintf("Некорректное поле фильтрации: %s", condition.Field), Field: condition.Field}
+	}
+	if !field.allows(condition.Operator) {
+		return "", nil, condition.error(fmt.Sprintf("Оператор %s недоступен для поля %s", condition.Operator, condition.Field))
+	}
+
+	column := field.Column
+	caseInsensitive := condition.CaseInsensitive && field.Type == String
+	if caseInsensitive {
+		column = "LOWER(" + column + ")"
+	}
+
+	switch condition.Operator {
+	case OperatorIsNull:
+		if condition.Value == "false" {
+			return field.Column + " IS NOT NULL", nil, nil
+		}
+		return field.Column + " IS NULL", nil, nil
+
+	case OperatorContains, OperatorStartsWith, OperatorEndsWith:
+		// Пустое значение означает, что фильтр не задан
+		if condition.Value == "" {
+			return "", nil, nil
+		}
+		pattern := escapeLike(condition.Value)
+		switch condition.Operator {
+		case OperatorContains:
+			pattern = "%" + pattern + "%"
+		case OperatorStartsWith:
+			pattern = pattern + "%"
+		case OperatorEndsWith:
+			pattern = "%" + pattern
+		}
+		like := " LIKE ?"
+		if caseInsensitive {
+			like = " ILIKE ?"
+		}
+		return field.Column + like, []interface{}{pattern}, nil
+
+	case OperatorIn:
+		if len(condition.Values) == 0 {
+			return "", nil, nil
+		}
+		values := []interface{}{}
+		for _, raw := range condition.Values {
+			value, err := field.parse(raw, caseInsensitive)
+			if err != nil {
+				return "", nil, condition.error(fmt.Sprintf("Некорректное значение для поля %s: %v", condition.Field, err))
+			}
+			values = append(values, value)
+		}
+		return column + " IN ?", []interface{}{values}, nil
+
+	case OperatorBetween:
+		if len(condition.Values) != 2 {
+			return "", nil, condition.error(fmt.Sprintf("Для оператора between нужно ровно 2 значения в values, передано %d", len(condition.Values)))
+		}
+		from, err := field.parse(condition.Values[0], caseInsensitive)
+		if err != nil {
+			return "", nil, condition.error(fmt.Sprintf("Некорректное значение для поля %s: %v", condition.Field, err))
+		}
+		to, err := field.parse(condition.Values[1], caseInsensitive)
+		if err != nil {
+			return "", nil, condition.error(fmt.Sprintf("Некорректное значение для поля %s: %v", condition.Field, err))
+		}
+		return column + " BETWEEN ? AND ?", []interface{}{from, to}, nil
+
+	default:
+		if condition.Value == "" {
+			return "", nil, nil
+		}
+		value, err := field.parse(condition.Value, caseInsensitive)
+		if err != nil {
+			return "", nil, condition.error(fmt.Sprintf("Некорректное значение для поля %s: %v", condition.Field, err))
+		}
+		return column + " " + comparisons[condition.Operator] + " ?", []interface{}{value}, nil
+	}
+}
+
+func (c Condition) error(message string) *Error {
+	return &Error{Message: message, Field: c.Field, Operator: c.Operator}
+}
+
+// Имена полей сравниваем без учета регистра, чтобы работали и "name", и "Name"
+func (s Schema) lookup(name string) (Field, bool) {
+	if field, ok := s[name]; ok {
+		return field, true
+	}
+	for key, field := range s {
+		if strings.EqualFold(key, name) {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+func (f Field) allows(operator string) bool {
+	for _, allowed := range typeOperators[f.Type] {
+		if allowed == operator {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Field) parse(value string, lower bool) (interface{}, error) {
+	switch f.Type {
+	case UUID:
+		return uuid.Parse(value)
+	case Date:
+		return time.Parse(time.RFC3339, value)
+	default:
+		if lower {
+			return strings.ToLower(value), nil
+		}
+		return value, nil
+	}
+}
+
+// Экранируем спецсимволы LIKE, чтобы значение искалось буквально
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -0,0 +1,214 @@
+package filter
+
+import (
+	"errors"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSchema = Schema{
+	"id":        {Column: "id", Type: UUID},
+	"name":      {Column: "name", Type: String},
+	"createdAt": {Column: "created_at", Type: Date},
+}
+
+func TestSchemaBuild(t *testing.T) {
+	createdAt := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		group    Group
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{name: "пустая группа", group: Group{}, wantSQL: ""},
+		{
+			name:     "равенство",
+			group:    Group{Conditions: []Condition{{Field: "name", Operator: OperatorEquals, Value: "Иван"}}},
+			wantSQL:  "name = ?",
+			wantArgs: []interface{}{"Иван"},
+		},
+		{
+			name:     "имя поля без учета регистра",
+			group:    Group{Conditions: []Condition{{Field: "CreatedAt", Operator: OperatorGte, Value: "2024-07-01T00:00:00Z"}}},
+			wantSQL:  "created_at >= ?",
+			wantArgs: []interface{}{createdAt},
+		},
+		{
+			name:     "спецсимволы LIKE ищутся буквально",
+			group:    Group{Conditions: []Condition{{Field: "name", Operator: OperatorContains, Value: `50%_\`, CaseInsensitive: true}}},
+			wantSQL:  "name ILIKE ?",
+			wantArgs: []interface{}{`%50\%\_\\%`},
+		},
+		{
+			name:     "значение с SQL передается параметром",
+			group:    Group{Conditions: []Condition{{Field: "name", Operator: OperatorEquals, Value: "x' OR '1'='1"}}},
+			wantSQL:  "name = ?",
+			wantArgs: []interface{}{"x' OR '1'='1"},
+		},
+		{
+			name:     "без учета регистра",
+			group:    Group{Conditions: []Condition{{Field: "name", Operator: OperatorIn, Values: []string{"Иван", "ПЕТР"}, CaseInsensitive: true}}},
+			wantSQL:  "LOWER(name) IN ?",
+			wantArgs: []interface{}{[]interface{}{"иван", "петр"}},
+		},
+		{
+			name:    "пустое значение не фильтрует",
+			group:   Group{Conditions: []Condition{{Field: "name", Operator: OperatorContains}, {Field: "id", Operator: OperatorIsNull, Value: "false"}}},
+			wantSQL: "id IS NOT NULL",
+		},
+		{
+			name: "вложенные группы",
+			group: Group{
+				Conditions: []Condition{{Field: "name", Operator: OperatorStartsWith, Value: "И"}},
+				Or: []Group{
+					{Conditions: []Condition{{Field: "createdAt", Operator: OperatorLt, Value: "2024-07-01T00:00:00Z"}}},
+					{Conditions: []Condition{{Field: "createdAt", Operator: OperatorIsNull}}},
+				},
+			},
+			wantSQL:  "name LIKE ? AND ((created_at < ?) OR (created_at IS NULL))",
+			wantArgs: []interface{}{"И%", createdAt},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := testSchema.build(tt.group, 0)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Fatalf("SQL %q, ожидался %q", sql, tt.wantSQL)
+			}
+			if len(args) != 0 || len(tt.wantArgs) != 0 {
+				if !reflect.DeepEqual(args, tt.wantArgs) {
+					t.Fatalf("параметры %#v, ожидались %#v", args, tt.wantArgs)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaBuildRejects(t *testing.T) {
+	nested := Group{}
+	for i := 0; i <= maxDepth+1; i++ {
+		nested = Group{And: []Group{nested}}
+	}
+
+	tests := []struct {
+		name      string
+		group     Group
+		wantField string
+	}{
+		{name: "поле вне схемы", group: Group{Conditions: []Condition{{Field: "password_hash", Operator: OperatorEquals, Value: "x"}}}, wantField: "password_hash"},
+		{name: "SQL вместо поля", group: Group{Conditions: []Condition{{Field: "name; DROP TABLE users --", Operator: OperatorEquals, Value: "x"}}}, wantField: "name; DROP TABLE users --"},
+		{name: "выражение вместо поля", group: Group{Conditions: []Condition{{Field: "1=1) OR (name", Operator: OperatorIsNull}}}, wantField: "1=1) OR (name"},
+		{name: "колонка вместо поля", group: Group{Conditions: []Condition{{Field: "created_at", Operator: OperatorIsNull}}}, wantField: "created_at"},
+		{name: "поле вне схемы во вложенной группе", group: Group{Or: []Group{{Conditions: []Condition{{Field: "role", Operator: OperatorEquals, Value: "admin"}}}}}, wantField: "role"},
+		{name: "неизвестный оператор", group: Group{Conditions: []Condition{{Field: "name", Operator: "= 1 OR 1 =", Value: "x"}}}, wantField: "name"},
+		{name: "оператор не для типа", group: Group{Conditions: []Condition{{Field: "id", Operator: OperatorContains, Value: "x"}}}, wantField: "id"},
+		{name: "некорректный UUID", group: Group{Conditions: []Condition{{Field: "id", Operator: OperatorEquals, Value: "1 OR 1=1"}}}, wantField: "id"},
+		{name: "некорректная дата", group: Group{Conditions: []Condition{{Field: "createdAt", Operator: OperatorGt, Value: "now()"}}}, wantField: "createdAt"},
+		{name: "between без пары значений", group: Group{Conditions: []Condition{{Field: "createdAt", Operator: OperatorBetween, Values: []string{"2024-07-01T00:00:00Z"}}}}, wantField: "createdAt"},
+		{name: "слишком глубокая вложенность", group: nested},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := testSchema.build(tt.group, 0)
+
+			var filterErr *Error
+			if !errors.As(err, &filterErr) {
+				t.Fatalf("ошибка %v, ожидалась *Error", err)
+			}
+			if filterErr.Field != tt.wantField {
+				t.Fatalf("поле в ошибке %q, ожидалось %q", filterErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	database, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost port=1 user=test dbname=test"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("не удалось создать БД: %v", err)
+	}
+	return database
+}
+
+type record struct {
+	ID   string
+	Name string
+}
+
+func TestSchemaOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		sorts     []Sort
+		wantOrder string
+		wantErr   bool
+	}{
+		{name: "только tieBreaker", wantOrder: "ORDER BY id"},
+		{name: "направление по умолчанию", sorts: []Sort{{Field: "name"}}, wantOrder: "ORDER BY name ASC,id"},
+		{name: "несколько полей", sorts: []Sort{{Field: "createdAt", Direction: "DESC"}, {Field: "name", Direction: "asc"}}, wantOrder: "ORDER BY created_at DESC,name ASC,id"},
+		{name: "поле вне схемы", sorts: []Sort{{Field: "passport_hash"}}, wantErr: true},
+		{name: "SQL в поле", sorts: []Sort{{Field: "name; DROP TABLE users"}}, wantErr: true},
+		{name: "SQL в направлении", sorts: []Sort{{Field: "name", Direction: "asc, (SELECT 1)"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := testSchema.Order(dryRunDB(t).Model(&record{}), tt.sorts, "id")
+			if tt.wantErr {
+				var filterErr *Error
+				if !errors.As(err, &filterErr) {
+					t.Fatalf("ошибка %v, ожидалась *Error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+
+			sql := query.Find(&[]record{}).Statement.SQL.String()
+			if !strings.HasSuffix(sql, tt.wantOrder) {
+				t.Fatalf("запрос %q не заканчивается на %q", sql, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestUserSchemaExcludesPassport(t *testing.T) {
+	if _, ok := UserSchema.lookup("passportSerie"); ok {
+		t.Fatal("паспортные данные доступны для фильтрации")
+	}
+	if _, ok := UserSchema.lookup("passportNumber"); ok {
+		t.Fatal("паспортные данные доступны для фильтрации")
+	}
+}
+
+func TestGroupSummaryOmitsValues(t *testing.T) {
+	group := Group{
+		Conditions: []Condition{{Field: "surname", Operator: OperatorEquals, Value: "Иванов"}},
+		Or: []Group{
+			{Conditions: []Condition{{Field: "address", Operator: OperatorContains, Value: "Ленина"}}},
+			{Conditions: []Condition{{Field: "name", Operator: OperatorIn, Values: []string{"Иван", "Петр"}}}},
+		},
+	}
+
+	want := []string{"surname equals", "address contains", "name in"}
+	if got := group.Summary(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("сводка %v, ожидалась %v", got, want)
+	}
+}
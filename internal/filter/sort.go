@@ -0,0 +1,41 @@
+package filter
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"strings"
+)
+
+const (
+	DirectionAsc  = "asc"
+	DirectionDesc = "desc"
+)
+
+type Sort struct {
+	Field     string
+	Direction string
+}
+
+// Order добавляет в запрос сортировку по полям схемы.
+// В конце всегда сортируем по tieBreaker, чтобы порядок строк был однозначным между страницами
+func (s Schema) Order(query *gorm.DB, sorts []Sort, tieBreaker string) (*gorm.DB, error) {
+	for _, sort := range sorts {
+		field, ok := s.lookup(sort.Field)
+		if !ok {
+			return nil, &Error{Message: fmt.Sprintf("Некорректное поле сортировки: %s", sort.Field), Field: sort.Field}
+		}
+
+		direction := strings.ToLower(sort.Direction)
+		switch direction {
+		case "":
+			direction = DirectionAsc
+		case DirectionAsc, DirectionDesc:
+		default:
+			return nil, &Error{Message: fmt.Sprintf("Некорректное направление сортировки: %s", sort.Direction), Field: sort.Field}
+		}
+
+		query = query.Order(field.Column + " " + strings.ToUpper(direction))
+	}
+
+	return query.Order(tieBreaker), nil
+}
@@ -0,0 +1,49 @@
+package filter
+
+import (
+	"gorm.io/gorm"
+	"test/internal/models"
+)
+
+// Поля пользователя, доступные для фильтрации и сортировки. Паспортные данные сюда намеренно не входят
+var UserSchema = Schema{
+	"id":         {Column: "id", Type: UUID},
+	"name":       {Column: "name", Type: String},
+	"surname":    {Column: "surname", Type: String},
+	"patronymic": {Column: "patronymic", Type: String},
+	"address":    {Column: "address", Type: String},
+	"createdAt":  {Column: "created_at", Type: Date},
+	"updatedAt":  {Column: "updated_at", Type: Date},
+	"deletedAt":  {Column: "deleted_at", Type: Date},
+}
+
+// ApplyUserFilters добавляет в запрос условия фильтрации пользователей.
+// Неизвестные поля и операторы возвращают *Error
+func ApplyUserFilters(query *gorm.DB, filters models.UserFilters) (*gorm.DB, error) {
+	return UserSchema.Apply(query, userGroup(filters))
+}
+
+// UserFilterSummary - поля и операторы фильтров пользователя без значений, для логов
+func UserFilterSummary(filters models.UserFilters) []string {
+	return userGroup(filters).Summary()
+}
+
+func userGroup(filters models.UserFilters) Group {
+	group := Group{}
+	for _, userFilter := range filters.Filters {
+		group.Conditions = append(group.Conditions, Condition{
+			Field:           userFilter.Field,
+			Value:           userFilter.Value,
+			Values:          userFilter.Values,
+			Operator:        userFilter.Operator,
+			CaseInsensitive: userFilter.CaseInsensitive,
+		})
+	}
+	for _, nested := range filters.And {
+		group.And = append(group.And, userGroup(nested))
+	}
+	for _, nested := range filters.Or {
+		group.Or = append(group.Or, userGroup(nested))
+	}
+	return group
+}
@@ -0,0 +1,14 @@
+package authentication
+
+import (
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"test/internal/auth"
+)
+
+// AuthHandler обрабатывает запросы /auth
+type AuthHandler struct {
+	DB   *gorm.DB
+	Log  logrus.FieldLogger
+	Auth *auth.Authenticator
+}
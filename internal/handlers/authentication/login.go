@@ -0,0 +1,56 @@
+package authentication
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"test/internal/auth"
+	"test/internal/models"
+	"time"
+)
+
+type LoginInput struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Password string    `json:"password"`
+}
+
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на вход пользователя")
+
+	var input LoginInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.Log.Errorf("Не удалось декодировать тело запроса: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось декодировать тело запроса: %v", err), 400)
+		return
+	}
+
+	h.Log.Debugf("Вход пользователя с ID %v", input.UserID)
+
+	// Отсутствующий пользователь и неверный пароль неотличимы для вызывающего
+	var user models.Users
+	if err := h.DB.First(&user, "id = ?", input.UserID).Error; err != nil || !auth.CheckPassword(user.PasswordHash, input.Password) {
+		h.Log.Errorf("Неудачная попытка входа пользователя %v", input.UserID)
+		http.Error(w, auth.ErrInvalidCredentials.Error(), 401)
+		return
+	}
+
+	token, expiresAt, err := h.Auth.IssueToken(user.ID)
+	if err != nil {
+		h.Log.Errorf("Не удалось выпустить токен: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось выпустить токен: %v", err), 500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"user_id":    user.ID.String(),
+		"token":      token,
+		"expires_at": expiresAt.Format(time.RFC3339),
+	})
+
+	h.Log.Info("Запрос на вход пользователя успешно завершен")
+
+	return
+}
@@ -0,0 +1,74 @@
+package apikeys
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"test/internal/auth"
+	"test/internal/models"
+)
+
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на создание ключа API")
+
+	var input models.APIKeysCreateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.Log.Errorf("Не удалось декодировать тело запроса: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось декодировать тело запроса: %v", err), 400)
+		return
+	}
+
+	if err := h.validator().ValidateCreateAPIKey(&input); err != nil {
+		h.Log.Errorf("Данные ключа API не прошли валидацию: %v", err)
+		http.Error(w, fmt.Sprintf("Данные ключа API не прошли валидацию: %v", err), 400)
+		return
+	}
+
+	key, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		h.Log.Errorf("Не удалось сгенерировать ключ API: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось сгенерировать ключ API: %v", err), 500)
+		return
+	}
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		h.Log.Errorf("Не удалось сгенерировать uuid для нового ключа API: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось сгенерировать uuid для нового ключа API: %v", err), 500)
+		return
+	}
+
+	apiKey := models.APIKeys{
+		ID:      id,
+		Name:    input.Name,
+		Prefix:  prefix,
+		KeyHash: hash,
+		Scopes:  input.Scopes,
+	}
+	if identity, ok := auth.FromContext(r.Context()); ok && identity.UserID != uuid.Nil {
+		apiKey.CreatedBy = &identity.UserID
+	}
+
+	if err = h.DB.Create(&apiKey).Error; err != nil {
+		h.Log.Errorf("Не удалось сохранить ключ API: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось сохранить ключ API: %v", err), 500)
+		return
+	}
+
+	h.Log.Debugf("Создан ключ API %v (%s) с областями %v", apiKey.ID, apiKey.Prefix, apiKey.Scopes)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	// Ключ целиком отдается только здесь, восстановить его потом нельзя
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"apiKey": apiKey,
+		"key":    key,
+		"msg":    "Ключ API создан. Сохраните его, повторно он показан не будет",
+	})
+
+	h.Log.Info("Запрос на создание ключа API успешно завершен")
+
+	return
+}
@@ -0,0 +1,35 @@
+package apikeys
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"test/internal/models"
+)
+
+func (h *APIKeyHandler) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на получение списка ключей API")
+
+	query := h.DB.Order("created_at DESC")
+	if r.URL.Query().Get("includeRevoked") != "true" {
+		query = query.Where("revoked_at IS NULL")
+	}
+
+	var apiKeys []models.APIKeys
+	if err := query.Find(&apiKeys).Error; err != nil {
+		h.Log.Errorf("Не удалось получить список ключей API: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось получить список ключей API: %v", err), 500)
+		return
+	}
+
+	h.Log.Debugf("Найдено ключей API: %d", len(apiKeys))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(apiKeys)
+
+	h.Log.Info("Запрос на получение списка ключей API успешно завершен")
+
+	return
+}
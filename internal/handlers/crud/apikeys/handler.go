@@ -0,0 +1,19 @@
+package apikeys
+
+import (
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"test/internal/clock"
+	"test/internal/validation"
+)
+
+// APIKeyHandler обрабатывает запросы /apiKeys
+type APIKeyHandler struct {
+	DB    *gorm.DB
+	Log   logrus.FieldLogger
+	Clock clock.Clock
+}
+
+func (h *APIKeyHandler) validator() *validation.Validator {
+	return &validation.Validator{DB: h.DB, Log: h.Log}
+}
@@ -0,0 +1,63 @@
+package apikeys
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"net/http"
+	"test/internal/models"
+)
+
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на отзыв ключа API")
+
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID ключа API: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID ключа API: %v", err), 400)
+		return
+	}
+
+	var apiKey models.APIKeys
+	if err = h.DB.First(&apiKey, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			h.Log.Errorf("Ключ API не найден: %v", err)
+			http.Error(w, "Ключ API не найден", 404)
+			return
+		}
+		h.Log.Errorf("Не удалось получить ключ API: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось получить ключ API: %v", err), 500)
+		return
+	}
+
+	if apiKey.RevokedAt != nil {
+		h.Log.Errorf("Ключ API %v уже отозван", id)
+		http.Error(w, "Ключ API уже отозван", 409)
+		return
+	}
+
+	// Условие на revoked_at защищает от двойного отзыва при параллельных запросах
+	result := h.DB.Model(&apiKey).Where("revoked_at IS NULL").Update("revoked_at", h.Clock.Now())
+	if result.Error != nil {
+		h.Log.Errorf("Не удалось отозвать ключ API: %v", result.Error)
+		http.Error(w, fmt.Sprintf("Не удалось отозвать ключ API: %v", result.Error), 500)
+		return
+	}
+	if result.RowsAffected == 0 {
+		h.Log.Errorf("Ключ API %v уже отозван", id)
+		http.Error(w, "Ключ API уже отозван", 409)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(map[string]string{"api_key_id": id.String(), "msg": "Ключ API отозван"})
+
+	h.Log.Info("Запрос на отзыв ключа API успешно завершен")
+
+	return
+}
@@ -0,0 +1,254 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+	"test/internal/auth"
+	"test/internal/models"
+	"test/internal/timetracking"
+)
+
+type AssignInput struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type TransferInput struct {
+	FromUserID uuid.UUID `json:"from_user_id"`
+	ToUserID   uuid.UUID `json:"to_user_id"`
+}
+
+func (h *TaskHandler) AssignUser(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на назначение пользователя на задачу")
+
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID задачи: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID задачи: %v", err), 400)
+		return
+	}
+
+	var input AssignInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.Log.Errorf("Не удалось декодировать тело запроса: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось декодировать тело запроса: %v", err), 400)
+		return
+	}
+
+	h.Log.Debugf("Назначение пользователя %v на задачу %v", input.UserID, id)
+
+	var task models.Tasks
+	if err := h.DB.First(&task, "id = ?", id).Error; err != nil {
+		h.Log.Errorf("Задача не найдена: %v", err)
+		http.Error(w, "Задача не найдена", 404)
+		return
+	}
+
+	if err := h.DB.First(&models.Users{}, "id = ?", input.UserID).Error; err != nil {
+		h.Log.Errorf("Пользователь не найден: %v", err)
+		http.Error(w, "Пользователь не найден", 404)
+		return
+	}
+
+	err = timetracking.Assign(h.DB.WithContext(r.Context()), task.ID, input.UserID)
+	if errors.Is(err, timetracking.ErrAlreadyAssigned) {
+		h.Log.Errorf("Не удалось назначить пользователя: %v", err)
+		http.Error(w, err.Error(), 409)
+		return
+	}
+	if err != nil {
+		h.Log.Errorf("Не удалось назначить пользователя: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось назначить пользователя: %v", err), 500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(map[string]string{"task_id": id.String(), "user_id": input.UserID.String(), "msg": "Пользователь назначен на задачу"})
+
+	h.Log.Info("Запрос на назначение пользователя на задачу успешно завершен")
+
+	return
+}
+
+func (h *TaskHandler) UnassignUser(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на снятие пользователя с задачи")
+
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID задачи: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID задачи: %v", err), 400)
+		return
+	}
+
+	user_id, err := uuid.Parse(vars["user_id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID пользователя: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID пользователя: %v", err), 400)
+		return
+	}
+
+	h.Log.Debugf("Снятие пользователя %v с задачи %v", user_id, id)
+
+	var task models.Tasks
+	if err = h.DB.First(&task, "id = ?", id).Error; err != nil {
+		h.Log.Errorf("Задача не найдена: %v", err)
+		http.Error(w, "Задача не найдена", 404)
+		return
+	}
+
+	err = h.DB.WithContext(r.Context()).Transaction(func(tx *gorm.DB) error {
+		entry, err := timetracking.Unassign(tx, task.ID, user_id, h.Clock.Now())
+		if err != nil || entry == nil {
+			return err
+		}
+
+		// Снятый пользователь работал над задачей - ставим её на паузу до следующего старта
+		if err = timetracking.Transition(tx, &task, models.TaskStatePaused); err != nil {
+			return err
+		}
+		if err = timetracking.ApplyTrackedTime(tx, &task); err != nil {
+			return err
+		}
+		return tx.Save(&task).Error
+	})
+	if errors.Is(err, timetracking.ErrNotAssigned) {
+		h.Log.Errorf("Не удалось снять пользователя с задачи: %v", err)
+		http.Error(w, err.Error(), 404)
+		return
+	}
+	if err != nil {
+		h.Log.Errorf("Не удалось снять пользователя с задачи: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось снять пользователя с задачи: %v", err), 500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(map[string]string{"task_id": id.String(), "user_id": user_id.String(), "msg": "Пользователь снят с задачи"})
+
+	h.Log.Info("Запрос на снятие пользователя с задачи успешно завершен")
+
+	return
+}
+
+func (h *TaskHandler) TransferTask(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на передачу задачи другому пользователю")
+
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID задачи: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID задачи: %v", err), 400)
+		return
+	}
+
+	var input TransferInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.Log.Errorf("Не удалось декодировать тело запроса: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось декодировать тело запроса: %v", err), 400)
+		return
+	}
+
+	h.Log.WithFields(logrus.Fields{
+		"task_id":      id,
+		"from_user_id": input.FromUserID,
+		"to_user_id":   input.ToUserID,
+	}).Debug("Данные для передачи задачи")
+
+	if input.FromUserID == input.ToUserID {
+		h.Log.Error("Задача передаётся тому же пользователю")
+		http.Error(w, "Задача передаётся тому же пользователю", 400)
+		return
+	}
+
+	var task models.Tasks
+	if err := h.DB.First(&task, "id = ?", id).Error; err != nil {
+		h.Log.Errorf("Задача не найдена: %v", err)
+		http.Error(w, "Задача не найдена", 404)
+		return
+	}
+
+	if err := h.DB.First(&models.Users{}, "id = ?", input.ToUserID).Error; err != nil {
+		h.Log.Errorf("Пользователь не найден: %v", err)
+		http.Error(w, "Пользователь не найден", 404)
+		return
+	}
+
+	err = h.DB.WithContext(r.Context()).Transaction(func(tx *gorm.DB) error {
+		now := h.Clock.Now()
+
+		entry, err := timetracking.Unassign(tx, task.ID, input.FromUserID, now)
+		if err != nil {
+			return err
+		}
+		if err = timetracking.Assign(tx, task.ID, input.ToUserID); err != nil {
+			return err
+		}
+
+		// Таймер не прерывается: сессия предыдущего исполнителя закрыта, новая открывается на нового
+		if entry != nil {
+			if _, err = timetracking.StartEntry(tx, task.ID, input.ToUserID, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, timetracking.ErrNotAssigned) {
+		h.Log.Errorf("Не удалось передать задачу: %v", err)
+		http.Error(w, err.Error(), 404)
+		return
+	}
+	if errors.Is(err, timetracking.ErrAlreadyAssigned) {
+		h.Log.Errorf("Не удалось передать задачу: %v", err)
+		http.Error(w, err.Error(), 409)
+		return
+	}
+	if err != nil {
+		h.Log.Errorf("Не удалось передать задачу: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось передать задачу: %v", err), 500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(map[string]string{"task_id": id.String(), "user_id": input.ToUserID.String(), "msg": "Задача передана пользователю"})
+
+	h.Log.Info("Запрос на передачу задачи успешно завершен")
+
+	return
+}
+
+// ID пользователя из параметра user_id. Если параметр не передан, возвращается uuid.Nil
+func queryUserID(r *http.Request) (uuid.UUID, error) {
+	value := r.URL.Query().Get("user_id")
+	if value == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(value)
+}
+
+// Пользователь, на которого записывается сессия таймера. Право указать другого пользователя
+// проверяет политика auth.OnBehalf на маршруте
+func timerUserID(r *http.Request) (uuid.UUID, error) {
+	requested, err := queryUserID(r)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	identity, _ := auth.FromContext(r.Context())
+	return auth.ActingUser(identity, requested), nil
+}
+
+func isAssigneeError(err error) bool {
+	return errors.Is(err, timetracking.ErrNotAssigned) ||
+		errors.Is(err, timetracking.ErrAssigneeRequired) ||
+		errors.Is(err, timetracking.ErrNoAssignees)
+}
@@ -0,0 +1,80 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+	"test/internal/models"
+	"test/internal/timetracking"
+)
+
+func (h *TaskHandler) CancelTask(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на отмену задачи")
+
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID задачи: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID задачи: %v", err), 400)
+		return
+	}
+
+	h.Log.Debugf("ID задачи для отмены %v", id)
+
+	var task models.Tasks
+	if err := h.DB.First(&task, "id = ?", id).Error; err != nil {
+		h.Log.Errorf("Задача не найдена: %v", err)
+		http.Error(w, "Задача не найдена", 404)
+		return
+	}
+
+	h.Log.WithFields(logrus.Fields{
+		"task_id":        task.ID,
+		"task_state":     task.State,
+		"task_startTime": task.StartTime,
+		"task_endTime":   task.EndTime,
+	}).Debug("Была найдена задача")
+
+	cancelTime := h.Clock.Now()
+
+	err = h.DB.WithContext(r.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := timetracking.Transition(tx, &task, models.TaskStateCancelled); err != nil {
+			return err
+		}
+
+		// Уже отработанное время сохраняется, открытая сессия закрывается
+		if _, err := timetracking.StopEntry(tx, task.ID, cancelTime); err != nil {
+			return err
+		}
+
+		if err := timetracking.ApplyTrackedTime(tx, &task); err != nil {
+			return err
+		}
+
+		return tx.Save(&task).Error
+	})
+
+	var transitionErr *timetracking.TransitionError
+	if errors.As(err, &transitionErr) {
+		h.writeStateConflict(w, transitionErr)
+		return
+	}
+	if err != nil {
+		h.Log.Errorf("Ошибка при обновлении задачи: %v", err)
+		http.Error(w, fmt.Sprintf("Ошибка при обновлении задачи: %v", err), 500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(task)
+
+	h.Log.Info("Запрос на отмену задачи успешно завершен")
+
+	return
+}
@@ -8,29 +8,27 @@ import (
 	"github.com/sirupsen/logrus"
 	"io"
 	"net/http"
-	"test/internal/db"
-	"test/internal/logging"
 	"test/internal/models"
 )
 
-func CreateTask(w http.ResponseWriter, r *http.Request) {
-	logging.Log.Info("Запрос на создание задания")
+func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на создание задания")
 
 	vars := mux.Vars(r)
 	user_id, err := uuid.Parse(vars["user_id"])
 	if err != nil {
-		logging.Log.WithFields(logrus.Fields{
+		h.Log.WithFields(logrus.Fields{
 			"errors": err,
 		}).Error("Не удалось перевести ID пользователя с UUID в String")
 		http.Error(w, fmt.Sprintf("Не удалось перевести ID пользователя с UUID в String: %v", err), 400)
 		return
 	}
 
-	logging.Log.Debugf("Задача будет создана для пользователя с ID - %v", user_id)
+	h.Log.Debugf("Задача будет создана для пользователя с ID - %v", user_id)
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		logging.Log.WithFields(logrus.Fields{
+		h.Log.WithFields(logrus.Fields{
 			"errors": err,
 		}).Error("Не удалось прочитать тело запроса")
 		http.Error(w, fmt.Sprintf("Не удалось прочитать тело запроса: %v", err), 400)
@@ -38,30 +36,40 @@ func CreateTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var task models.Tasks
-	task.ID, err = uuid.NewUUID()
-	if err != nil {
-		logging.Log.WithFields(logrus.Fields{
+	if err = json.Unmarshal(body, &task); err != nil {
+		h.Log.WithFields(logrus.Fields{
 			"errors": err,
-		}).Error("Не удалось сгенерировать uuid для нового задания")
-		http.Error(w, fmt.Sprintf("Не удалось сгенерировать uuid для нового задания: %v", err), 500)
+		}).Error("Не удалось декодировать тело запроса в структуру Tasks")
+		http.Error(w, fmt.Sprintf("Не удалось декодировать тело запроса в структуру Tasks: %v", err), 400)
 		return
 	}
 
-	logging.Log.Debugf("Сгенерирован uuid для нового задания - %v", task.ID)
+	if err = h.validator().ValidateCreateTask(&task); err != nil {
+		h.Log.WithFields(logrus.Fields{
+			"errors": err,
+		}).Error("Данные задачи не прошли валидацию")
+		http.Error(w, fmt.Sprintf("Данные задачи не прошли валидацию: %v", err), 400)
+		return
+	}
 
-	if err = json.Unmarshal(body, &task); err != nil {
-		logging.Log.WithFields(logrus.Fields{
+	// Из тела берутся только название и описание: id, состояние и учтенное время задает сервер
+	task = models.Tasks{Name: task.Name, Description: task.Description, State: models.TaskStateNew}
+	task.ID, err = uuid.NewUUID()
+	if err != nil {
+		h.Log.WithFields(logrus.Fields{
 			"errors": err,
-		}).Error("Не удалось декодировать тело запроса в структуру Tasks")
-		http.Error(w, fmt.Sprintf("Не удалось декодировать тело запроса в структуру Tasks: %v", err), 400)
+		}).Error("Не удалось сгенерировать uuid для нового задания")
+		http.Error(w, fmt.Sprintf("Не удалось сгенерировать uuid для нового задания: %v", err), 500)
 		return
 	}
 
-	logging.Log.WithFields(logrus.Fields{
+	h.Log.Debugf("Сгенерирован uuid для нового задания - %v", task.ID)
+
+	h.Log.WithFields(logrus.Fields{
 		"task_id":          task.ID,
 		"task_name":        task.Name,
 		"task_description": task.Description,
-		"task_status":      task.Status,
+		"task_state":       task.State,
 		"task_hours":       task.Hours,
 		"task_minutes":     task.Minutes,
 		"task_seconds":     task.Seconds,
@@ -71,8 +79,8 @@ func CreateTask(w http.ResponseWriter, r *http.Request) {
 		"task_updatedAt":   task.UpdatedAt,
 	}).Debug("Данные для создания записи задания")
 
-	if err = db.PostgresClient.Create(&task).Error; err != nil {
-		logging.Log.WithFields(logrus.Fields{
+	if err = h.DB.Create(&task).Error; err != nil {
+		h.Log.WithFields(logrus.Fields{
 			"errors": err,
 		}).Error("Неудалось создать задание")
 		http.Error(w, fmt.Sprintf("Неудалось создать задание: %v", err), 500)
@@ -85,15 +93,15 @@ func CreateTask(w http.ResponseWriter, r *http.Request) {
 	}
 	user_tasks.ID, err = uuid.NewUUID()
 	if err != nil {
-		logging.Log.WithFields(logrus.Fields{
+		h.Log.WithFields(logrus.Fields{
 			"errors": err,
 		}).Error("Не удалось сгенерировать uuid для записи связи пользователя и задания")
 		http.Error(w, fmt.Sprintf("Не удалось сгенерировать uuid для записи связи пользователя и задания: %v", err), 500)
 		return
 	}
 
-	if err = db.PostgresClient.Create(&user_tasks).Error; err != nil {
-		logging.Log.WithFields(logrus.Fields{
+	if err = h.DB.Create(&user_tasks).Error; err != nil {
+		h.Log.WithFields(logrus.Fields{
 			"errors": err,
 		}).Error("Неудалось создать связь между пользователем и заданием")
 		http.Error(w, fmt.Sprintf("Неудалось создать связь между пользователем и заданием: %v", err), 500)
@@ -104,7 +112,7 @@ func CreateTask(w http.ResponseWriter, r *http.Request) {
 
 	json.NewEncoder(w).Encode(map[string]string{"task_id": task.ID.String(), "msg": "Создание задания прошло успешно"})
 
-	logging.Log.Info("Запрос на создание задания успешно завершен")
+	h.Log.Info("Запрос на создание задания успешно завершен")
 
 	return
 }
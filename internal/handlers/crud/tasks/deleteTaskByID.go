@@ -0,0 +1,78 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"net/http"
+	"test/internal/models"
+	"test/internal/timetracking"
+)
+
+func (h *TaskHandler) DeleteTaskByID(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на удаление задачи по ID")
+
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID задачи: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID задачи: %v", err), 400)
+		return
+	}
+
+	h.Log.Debugf("ID задачи на удаление %v", id)
+
+	var task models.Tasks
+	if err := h.DB.First(&task, "id = ?", id).Error; err != nil {
+		h.Log.Errorf("Задача не найдена: %v", err)
+		http.Error(w, "Задача не найдена", 404)
+		return
+	}
+
+	// Задача удаляется мягко: запись остаётся в БД с заполненным DeletedAt,
+	// а запущенный таймер останавливается, чтобы сессия не осталась открытой навсегда.
+	// Задача в работе или на паузе при этом отменяется, чтобы в истории она не осталась выполняемой.
+	err = h.DB.WithContext(r.Context()).Transaction(func(tx *gorm.DB) error {
+		if task.State == models.TaskStateInProgress || task.State == models.TaskStatePaused {
+			if err := timetracking.Transition(tx, &task, models.TaskStateCancelled); err != nil {
+				return err
+			}
+		}
+
+		if _, err := timetracking.StopEntry(tx, task.ID, h.Clock.Now()); err != nil {
+			return err
+		}
+
+		if err := timetracking.ApplyTrackedTime(tx, &task); err != nil {
+			return err
+		}
+
+		if err := tx.Save(&task).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&task).Error
+	})
+
+	var transitionErr *timetracking.TransitionError
+	if errors.As(err, &transitionErr) {
+		h.writeStateConflict(w, transitionErr)
+		return
+	}
+	if err != nil {
+		h.Log.Errorf("Не удалось удалить задачу %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось удалить задачу: %v", err), 500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(map[string]string{"task_id": id.String(), "msg": "Удаление задачи прошло успешно"})
+
+	h.Log.Info("Запрос на удаление задачи по ID успешно завершён")
+
+	return
+}
@@ -0,0 +1,54 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"test/internal/models"
+)
+
+func (h *TaskHandler) GetTaskByID(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на получение задачи по ID")
+
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID задачи: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID задачи: %v", err), 400)
+		return
+	}
+
+	h.Log.Debugf("ID задачи на получение %v", id)
+
+	var task models.Tasks
+	if err := h.DB.First(&task, "id = ?", id).Error; err != nil {
+		h.Log.Errorf("Задача не найдена: %v", err)
+		http.Error(w, "Задача не найдена", 404)
+		return
+	}
+
+	h.Log.WithFields(logrus.Fields{
+		"task_id":          task.ID,
+		"task_name":        task.Name,
+		"task_description": task.Description,
+		"task_state":       task.State,
+		"task_hours":       task.Hours,
+		"task_minutes":     task.Minutes,
+		"task_seconds":     task.Seconds,
+		"task_startTime":   task.StartTime,
+		"task_endTime":     task.EndTime,
+		"task_createdAt":   task.CreatedAt,
+		"task_updatedAt":   task.UpdatedAt,
+	}).Debug("Получена задача со следующими данными")
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(task)
+
+	h.Log.Info("Запрос на получение задачи по ID успешно завершён")
+
+	return
+}
@@ -0,0 +1,48 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"test/internal/models"
+)
+
+func (h *TaskHandler) GetTaskHistory(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на получение истории состояний задачи")
+
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID задачи: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID задачи: %v", err), 400)
+		return
+	}
+
+	h.Log.Debugf("ID задачи для получения истории %v", id)
+
+	var task models.Tasks
+	if err := h.DB.First(&task, "id = ?", id).Error; err != nil {
+		h.Log.Errorf("Задача не найдена: %v", err)
+		http.Error(w, "Задача не найдена", 404)
+		return
+	}
+
+	var transitions []models.TaskStateTransitions
+	if err := h.DB.Where("task_id = ?", task.ID).Order("created_at").Find(&transitions).Error; err != nil {
+		h.Log.Errorf("Не удалось получить историю состояний задачи %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось получить историю состояний задачи: %v", err), 500)
+		return
+	}
+
+	h.Log.Debugf("Получена история состояний задачи: %v", transitions)
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(transitions)
+
+	h.Log.Info("Запрос на получение истории состояний задачи успешно завершен")
+
+	return
+}
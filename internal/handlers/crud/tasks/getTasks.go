@@ -0,0 +1,119 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"test/internal/auth"
+	"test/internal/filter"
+	"test/internal/models"
+	"test/internal/pagination"
+	"time"
+)
+
+// Поля задачи, доступные для фильтрации
+var taskFilterSchema = filter.Schema{
+	"id":          {Column: "id", Type: filter.UUID},
+	"name":        {Column: "name", Type: filter.String},
+	"description": {Column: "description", Type: filter.String},
+	"state":       {Column: "state", Type: filter.String},
+	"createdAt":   {Column: "created_at", Type: filter.Date},
+	"updatedAt":   {Column: "updated_at", Type: filter.Date},
+}
+
+func (h *TaskHandler) GetTasks(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на получение списка задач")
+
+	// Извлекаем параметры фильтрации из запроса, если они есть
+	input := models.TaskGetListInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && err != io.EOF {
+		h.Log.Errorf("Ошибка при декодировании параметров фильтрации: %v", err)
+		http.Error(w, fmt.Sprintf("Ошибка при декодировании параметров фильтрации: %v", err), 400)
+		return
+	}
+
+	h.Log.WithFields(logrus.Fields{
+		"page":       input.Page,
+		"limit":      input.Limit,
+		"filters":    input.Filters,
+		"pagination": input.Pagination,
+		"cursor":     input.Cursor,
+	}).Debug("Получен Input со следующими данными")
+
+	params, err := pagination.Parse(input.Pagination, input.Cursor, input.Page, input.Limit)
+	if err != nil {
+		h.Log.Errorf("Некорректные параметры пагинации: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	h.Log.Debugf("Page=%d, Limit=%d, Offset=%d, Cursor=%v", params.Page, params.Limit, params.Offset(), params.Cursor)
+
+	identity, _ := auth.FromContext(r.Context())
+	query := auth.ScopeTasks(h.DB.Model(&models.Tasks{}), identity)
+
+	group := filter.Group{}
+	for _, taskFilter := range input.Filters.Filters {
+		group.Conditions = append(group.Conditions, filter.Condition{
+			Field:           taskFilter.Field,
+			Value:           taskFilter.Value,
+			Values:          taskFilter.Values,
+			Operator:        taskFilter.Operator,
+			CaseInsensitive: taskFilter.CaseInsensitive,
+		})
+	}
+
+	query, err = taskFilterSchema.Apply(query, group)
+	var filterErr *filter.Error
+	if errors.As(err, &filterErr) {
+		h.Log.Errorf("Некорректные параметры фильтрации: %v", err)
+		filter.WriteError(w, filterErr)
+		return
+	}
+
+	var total int64
+	if !params.Cursor {
+		// Сессия нужна, чтобы подсчет общего количества и выборка страницы не мешали друг другу
+		query = query.Session(&gorm.Session{})
+
+		if err = query.Count(&total).Error; err != nil {
+			h.Log.Errorf("Не удалось посчитать количество задач %v", err)
+			http.Error(w, fmt.Sprintf("Не удалось посчитать количество задач: %v", err), 400)
+			return
+		}
+	}
+
+	query, err = params.Window(query, nil)
+	if err != nil {
+		h.Log.Errorf("Некорректные параметры пагинации: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	tasks := []models.Tasks{}
+	if err = query.Find(&tasks).Error; err != nil {
+		h.Log.Errorf("Не удалось получить список задач %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось получить список задач: %v", err), 400)
+		return
+	}
+
+	tasks, envelope := pagination.Result(params, tasks, total, func(task models.Tasks) (time.Time, uuid.UUID) {
+		return task.CreatedAt, task.ID
+	})
+	response := models.TaskListResponse{Items: tasks, Envelope: envelope}
+
+	h.Log.Debugf("Получены следующие задачи: \n %v", tasks)
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(response)
+
+	h.Log.Info("Запрос на получение списка задач успешно выполнен")
+
+	return
+}
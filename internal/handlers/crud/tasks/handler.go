@@ -0,0 +1,19 @@
+package tasks
+
+import (
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"test/internal/clock"
+	"test/internal/validation"
+)
+
+// TaskHandler обрабатывает запросы /tasks
+type TaskHandler struct {
+	DB    *gorm.DB
+	Log   logrus.FieldLogger
+	Clock clock.Clock
+}
+
+func (h *TaskHandler) validator() *validation.Validator {
+	return &validation.Validator{DB: h.DB, Log: h.Log}
+}
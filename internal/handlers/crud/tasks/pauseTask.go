@@ -0,0 +1,82 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+	"test/internal/models"
+	"test/internal/timetracking"
+)
+
+func (h *TaskHandler) PauseTaskTimer(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на паузу задачи")
+
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID задачи: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID задачи: %v", err), 400)
+		return
+	}
+
+	h.Log.Debugf("ID задачи для паузы %v", id)
+
+	var task models.Tasks
+	if err := h.DB.First(&task, "id = ?", id).Error; err != nil {
+		h.Log.Errorf("Задача не найдена: %v", err)
+		http.Error(w, "Задача не найдена", 404)
+		return
+	}
+
+	h.Log.WithFields(logrus.Fields{
+		"task_id":        task.ID,
+		"task_state":     task.State,
+		"task_startTime": task.StartTime,
+		"task_endTime":   task.EndTime,
+	}).Debug("Была найдена задача")
+
+	pauseTime := h.Clock.Now()
+
+	err = h.DB.WithContext(r.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := timetracking.Transition(tx, &task, models.TaskStatePaused); err != nil {
+			return err
+		}
+
+		if _, err := timetracking.StopEntry(tx, task.ID, pauseTime); err != nil {
+			return err
+		}
+
+		// EndTime не трогаем: задача не завершена, а исходное время старта сохраняется
+		if err := timetracking.ApplyTrackedTime(tx, &task); err != nil {
+			return err
+		}
+
+		return tx.Save(&task).Error
+	})
+
+	var transitionErr *timetracking.TransitionError
+	if errors.As(err, &transitionErr) {
+		h.writeStateConflict(w, transitionErr)
+		return
+	}
+	if err != nil {
+		h.Log.Errorf("Ошибка при обновлении задачи: %v", err)
+		http.Error(w, fmt.Sprintf("Ошибка при обновлении задачи: %v", err), 500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(task)
+
+	h.Log.Infof("Отсчет времени для задачи %s приостановлен", id)
+
+	h.Log.Info("Запрос на паузу задачи успешно завершен")
+
+	return
+}
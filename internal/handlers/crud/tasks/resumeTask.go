@@ -0,0 +1,100 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+	"test/internal/models"
+	"test/internal/timetracking"
+)
+
+func (h *TaskHandler) ResumeTaskTimer(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на возобновление задачи")
+
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID задачи: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID задачи: %v", err), 400)
+		return
+	}
+
+	h.Log.Debugf("ID задачи для возобновления %v", id)
+
+	// Пользователь, на которого будет записана сессия. По умолчанию - сам вызывающий
+	requestedUserID, err := timerUserID(r)
+	if err != nil {
+		h.Log.Errorf("Некорректный ID пользователя: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID пользователя: %v", err), 400)
+		return
+	}
+
+	var task models.Tasks
+	if err = h.DB.First(&task, "id = ?", id).Error; err != nil {
+		h.Log.Errorf("Задача не найдена: %v", err)
+		http.Error(w, "Задача не найдена", 404)
+		return
+	}
+
+	h.Log.WithFields(logrus.Fields{
+		"task_id":        task.ID,
+		"task_state":     task.State,
+		"task_startTime": task.StartTime,
+		"task_endTime":   task.EndTime,
+	}).Debug("Была найдена задача")
+
+	// Возобновить можно только задачу на паузе, новую задачу запускает StartTaskTimer
+	if task.State != models.TaskStatePaused {
+		h.writeStateConflict(w, &timetracking.TransitionError{From: task.State, To: models.TaskStateInProgress})
+		return
+	}
+
+	resumeTime := h.Clock.Now()
+
+	err = h.DB.WithContext(r.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := timetracking.Transition(tx, &task, models.TaskStateInProgress); err != nil {
+			return err
+		}
+
+		userID, err := timetracking.ResolveTaskUser(tx, task.ID, requestedUserID)
+		if err != nil {
+			return err
+		}
+		if _, err = timetracking.StartEntry(tx, task.ID, userID, resumeTime); err != nil {
+			return err
+		}
+
+		return tx.Save(&task).Error
+	})
+
+	var transitionErr *timetracking.TransitionError
+	if errors.As(err, &transitionErr) {
+		h.writeStateConflict(w, transitionErr)
+		return
+	}
+	if isAssigneeError(err) {
+		h.Log.Errorf("Не удалось определить исполнителя задачи: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if err != nil {
+		h.Log.Errorf("Ошибка при обновлении задачи: %v", err)
+		http.Error(w, fmt.Sprintf("Ошибка при обновлении задачи: %v", err), 500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(task)
+
+	h.Log.Infof("Отсчет времени для задачи %s возобновлён", id)
+
+	h.Log.Info("Запрос на возобновление задачи успешно завершен")
+
+	return
+}
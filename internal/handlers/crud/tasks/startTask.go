@@ -2,36 +2,50 @@ package tasks
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 	"net/http"
-	"test/internal/db"
-	"test/internal/logging"
 	"test/internal/models"
-	"time"
+	"test/internal/timetracking"
 )
 
-func StartTaskTimer(w http.ResponseWriter, r *http.Request) {
-	logging.Log.Info("Запрос на старт задачи")
+func (h *TaskHandler) StartTaskTimer(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на старт задачи")
 
 	vars := mux.Vars(r)
-	id := vars["id"]
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID задачи: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID задачи: %v", err), 400)
+		return
+	}
 
-	logging.Log.Debugf("ID задачи для старта %v", id)
+	h.Log.Debugf("ID задачи для старта %v", id)
+
+	// Пользователь, на которого будет записана сессия. По умолчанию - сам вызывающий
+	requestedUserID, err := timerUserID(r)
+	if err != nil {
+		h.Log.Errorf("Некорректный ID пользователя: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID пользователя: %v", err), 400)
+		return
+	}
 
 	var task models.Tasks
-	if err := db.PostgresClient.First(&task, "id = ?", id).Error; err != nil {
-		logging.Log.Errorf("Задача не найдена: %v", err)
+	if err = h.DB.First(&task, "id = ?", id).Error; err != nil {
+		h.Log.Errorf("Задача не найдена: %v", err)
 		http.Error(w, "Задача не найдена", 404)
 		return
 	}
 
-	logging.Log.WithFields(logrus.Fields{
+	h.Log.WithFields(logrus.Fields{
 		"task_id":          task.ID,
 		"task_name":        task.Name,
 		"task_description": task.Description,
-		"task_status":      task.Status,
+		"task_state":       task.State,
 		"task_hours":       task.Hours,
 		"task_minutes":     task.Minutes,
 		"task_seconds":     task.Seconds,
@@ -41,12 +55,41 @@ func StartTaskTimer(w http.ResponseWriter, r *http.Request) {
 		"task_updatedAt":   task.UpdatedAt,
 	}).Debug("Была найдена задача")
 
-	startTime := time.Now()
-	task.StartTime = &startTime
-	task.Status = true
+	startTime := h.Clock.Now()
+
+	err = h.DB.WithContext(r.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := timetracking.Transition(tx, &task, models.TaskStateInProgress); err != nil {
+			return err
+		}
+
+		userID, err := timetracking.ResolveTaskUser(tx, task.ID, requestedUserID)
+		if err != nil {
+			return err
+		}
+		if _, err = timetracking.StartEntry(tx, task.ID, userID, startTime); err != nil {
+			return err
+		}
 
-	if err := db.PostgresClient.Save(&task).Error; err != nil {
-		logging.Log.Errorf("Ошибка при обновлении задачи: %v", err)
+		// Время первого старта не перезаписываем
+		if task.StartTime == nil {
+			task.StartTime = &startTime
+		}
+
+		return tx.Save(&task).Error
+	})
+
+	var transitionErr *timetracking.TransitionError
+	if errors.As(err, &transitionErr) {
+		h.writeStateConflict(w, transitionErr)
+		return
+	}
+	if isAssigneeError(err) {
+		h.Log.Errorf("Не удалось определить исполнителя задачи: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if err != nil {
+		h.Log.Errorf("Ошибка при обновлении задачи: %v", err)
 		http.Error(w, fmt.Sprintf("Ошибка при обновлении задачи: %v", err), 500)
 		return
 	}
@@ -55,9 +98,9 @@ func StartTaskTimer(w http.ResponseWriter, r *http.Request) {
 
 	json.NewEncoder(w).Encode(task)
 
-	logging.Log.Infof("Отсчет времени для задачи %s начат", id)
+	h.Log.Infof("Отсчет времени для задачи %s начат", id)
 
-	logging.Log.Info("Запрос на старт задачи успешно завершен")
+	h.Log.Info("Запрос на старт задачи успешно завершен")
 
 	return
 }
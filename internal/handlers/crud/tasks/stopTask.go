@@ -2,34 +2,40 @@ package tasks
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 	"net/http"
-	"test/internal/db"
-	"test/internal/logging"
 	"test/internal/models"
-	"time"
+	"test/internal/timetracking"
 )
 
-func StopTaskTimer(w http.ResponseWriter, r *http.Request) {
-	logging.Log.Info("Запрос на остановку задачи")
+func (h *TaskHandler) StopTaskTimer(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на остановку задачи")
 
 	vars := mux.Vars(r)
-	id := vars["id"]
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID задачи: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID задачи: %v", err), 400)
+		return
+	}
 
 	var task models.Tasks
-	if err := db.PostgresClient.First(&task, "id = ?", id).Error; err != nil {
-		logging.Log.Errorf("Задача не найдена: %v", err)
+	if err := h.DB.First(&task, "id = ?", id).Error; err != nil {
+		h.Log.Errorf("Задача не найдена: %v", err)
 		http.Error(w, "Задача не найдена", 404)
 		return
 	}
 
-	logging.Log.WithFields(logrus.Fields{
+	h.Log.WithFields(logrus.Fields{
 		"task_id":          task.ID,
 		"task_name":        task.Name,
 		"task_description": task.Description,
-		"task_status":      task.Status,
+		"task_state":       task.State,
 		"task_hours":       task.Hours,
 		"task_minutes":     task.Minutes,
 		"task_seconds":     task.Seconds,
@@ -39,12 +45,33 @@ func StopTaskTimer(w http.ResponseWriter, r *http.Request) {
 		"task_updatedAt":   task.UpdatedAt,
 	}).Debug("Была найдена задача")
 
-	endTime := time.Now()
-	task.EndTime = &endTime
-	task.Status = false
+	endTime := h.Clock.Now()
+
+	err = h.DB.WithContext(r.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := timetracking.Transition(tx, &task, models.TaskStateDone); err != nil {
+			return err
+		}
+
+		if _, err := timetracking.StopEntry(tx, task.ID, endTime); err != nil {
+			return err
+		}
+
+		task.EndTime = &endTime
 
-	if err := db.PostgresClient.Save(&task).Error; err != nil {
-		logging.Log.Errorf("Ошибка при обновлении задачи: %v", err)
+		if err := timetracking.ApplyTrackedTime(tx, &task); err != nil {
+			return err
+		}
+
+		return tx.Save(&task).Error
+	})
+
+	var transitionErr *timetracking.TransitionError
+	if errors.As(err, &transitionErr) {
+		h.writeStateConflict(w, transitionErr)
+		return
+	}
+	if err != nil {
+		h.Log.Errorf("Ошибка при обновлении задачи: %v", err)
 		http.Error(w, fmt.Sprintf("Ошибка при обновлении задачи: %v", err), 500)
 		return
 	}
@@ -53,9 +80,9 @@ func StopTaskTimer(w http.ResponseWriter, r *http.Request) {
 
 	json.NewEncoder(w).Encode(task)
 
-	logging.Log.Infof("Отсчет времени для задачи %s закончен", id)
+	h.Log.Infof("Отсчет времени для задачи %s закончен", id)
 
-	logging.Log.Info("Запрос на остановку задачи успешно завершен")
+	h.Log.Info("Запрос на остановку задачи успешно завершен")
 
 	return
 }
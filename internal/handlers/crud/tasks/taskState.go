@@ -0,0 +1,17 @@
+package tasks
+
+import (
+	"encoding/json"
+	"net/http"
+	"test/internal/timetracking"
+)
+
+// Отвечаем 409 с текущим состоянием задачи, если переход недопустим
+func (h *TaskHandler) writeStateConflict(w http.ResponseWriter, err *timetracking.TransitionError) {
+	h.Log.Errorf("Недопустимый переход состояния задачи: %v", err)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error(), "state": string(err.From)})
+}
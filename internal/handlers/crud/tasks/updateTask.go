@@ -0,0 +1,83 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"test/internal/models"
+)
+
+func (h *TaskHandler) UpdateTaskByID(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на обновление данных задачи")
+
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID задачи: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID задачи: %v", err), 400)
+		return
+	}
+
+	h.Log.Debugf("ID задачи на обновление данных %v", id)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.Log.WithFields(logrus.Fields{
+			"errors": err,
+		}).Error("Не удалось прочитать тело запроса")
+		http.Error(w, fmt.Sprintf("Не удалось прочитать тело запроса: %v", err), 400)
+		return
+	}
+
+	var input models.TasksUpdateInput
+	if err = json.Unmarshal(body, &input); err != nil {
+		h.Log.WithFields(logrus.Fields{
+			"errors": err,
+		}).Error("Не удалось декодировать тело запроса в структуру TasksUpdateInput")
+		http.Error(w, fmt.Sprintf("Не удалось декодировать тело запроса в структуру TasksUpdateInput: %v", err), 400)
+		return
+	}
+
+	if err = h.validator().ValidateUpdateTask(&input); err != nil {
+		h.Log.WithFields(logrus.Fields{
+			"errors": err,
+		}).Error("Данные задачи не прошли валидацию")
+		http.Error(w, fmt.Sprintf("Данные задачи не прошли валидацию: %v", err), 400)
+		return
+	}
+
+	var task models.Tasks
+	if err = h.DB.First(&task, "id = ?", id).Error; err != nil {
+		h.Log.Errorf("Задача не найдена: %v", err)
+		http.Error(w, "Задача не найдена", 404)
+		return
+	}
+
+	h.Log.WithFields(logrus.Fields{
+		"task_name":        input.Name,
+		"task_description": input.Description,
+	}).Debugf("Данные для обновления записи задачи с ID: %v", id)
+
+	// Обновляем через map, чтобы можно было очистить описание
+	err = h.DB.Model(&task).Updates(map[string]interface{}{
+		"name":        input.Name,
+		"description": input.Description,
+	}).Error
+	if err != nil {
+		h.Log.Errorf("Не удалось обновить данные задачи %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось обновить данные задачи: %v", err), 500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(task)
+
+	h.Log.Info("Запрос на обновление данных задачи успешно завершен")
+
+	return
+}
@@ -2,61 +2,89 @@ package users
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"io"
 	"net/http"
-	"test/internal/db"
-	"test/internal/logging"
 	"test/internal/models"
+	"test/internal/peopleinfo"
 	"test/internal/validation"
 )
 
-func CreateUser(w http.ResponseWriter, r *http.Request) {
-	logging.Log.Info("Запрос на создание пользователя")
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на создание пользователя")
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		logging.Log.WithFields(logrus.Fields{
+		h.Log.WithFields(logrus.Fields{
 			"errors": err,
 		}).Error("Не удалось прочитать тело запроса")
 		http.Error(w, fmt.Sprintf("Не удалось прочитать тело запроса: %v", err), 400)
 		return
 	}
 
-	var user models.Users
+	var input models.UsersCreateInput
+	if err = json.Unmarshal(body, &input); err != nil {
+		h.Log.WithFields(logrus.Fields{
+			"errors": err,
+		}).Error("Не удалось декодировать тело запроса в структуру UsersCreateInput")
+		http.Error(w, fmt.Sprintf("Не удалось декодировать тело запроса в структуру UsersCreateInput: %v", err), 400)
+		return
+	}
+
+	// Из тела берутся только ФИО, адрес и паспорт: id задает сервер, а роль и руководителя - администратор отдельным запросом
+	user := models.Users{
+		Name:           input.Name,
+		Surname:        input.Surname,
+		Patronymic:     input.Patronymic,
+		Address:        input.Address,
+		PassportSerie:  input.PassportSerie,
+		PassportNumber: input.PassportNumber,
+	}
 
 	user.ID, err = uuid.NewUUID()
 	if err != nil {
-		logging.Log.WithFields(logrus.Fields{
+		h.Log.WithFields(logrus.Fields{
 			"errors": err,
 		}).Error("Не удалось сгенерировать uuid для нового пользователя")
 		http.Error(w, fmt.Sprintf("Не удалось сгенерировать uuid для нового пользователя: %v", err), 500)
 		return
 	}
 
-	logging.Log.Debugf("Сгенерирован uuid для нового пользователя - %v", user.ID)
+	h.Log.Debugf("Сгенерирован uuid для нового пользователя - %v", user.ID)
 
-	if err = json.Unmarshal(body, &user); err != nil {
-		logging.Log.WithFields(logrus.Fields{
-			"errors": err,
-		}).Error("Не удалось декодировать тело запроса в структуру Users")
-		http.Error(w, fmt.Sprintf("Не удалось декодировать тело запроса в структуру Users: %v", err), 400)
-		return
+	if isEnrichRequest(&user) {
+		if err = h.enrichUser(r.Context(), &user); err != nil {
+			h.Log.WithFields(logrus.Fields{
+				"errors": err,
+			}).Error("Не удалось получить данные пользователя по паспорту")
+
+			switch {
+			case errors.Is(err, peopleinfo.ErrNotFound):
+				http.Error(w, err.Error(), 422)
+			case errors.Is(err, peopleinfo.ErrNotConfigured):
+				http.Error(w, err.Error(), 503)
+			default:
+				http.Error(w, fmt.Sprintf("Не удалось получить данные пользователя по паспорту: %v", err), 502)
+			}
+			return
+		}
 	}
 
 	user.FullPassport = user.PassportSerie + user.PassportNumber
+	user.PassportHash = h.Cipher.BlindIndex(user.FullPassport)
 
-	if err = validation.ValidateCreateUser(&user); err != nil {
-		logging.Log.WithFields(logrus.Fields{
+	if err = h.validator().ValidateCreateUser(&user); err != nil {
+		h.Log.WithFields(logrus.Fields{
 			"errors": err,
 		}).Error("Данные пользователя не прошли валидацию")
 		http.Error(w, fmt.Sprintf("Данные пользователя не прошли валидацию: %v", err), 400)
 		return
 	}
 
-	logging.Log.WithFields(logrus.Fields{
+	h.Log.WithFields(logrus.Fields{
 		"user_id":             user.ID,
 		"user_name":           user.Name,
 		"user_surname":        user.Surname,
@@ -69,8 +97,13 @@ func CreateUser(w http.ResponseWriter, r *http.Request) {
 		"user_updatedAt":      user.UpdatedAt,
 	}).Debug("Данные для создания записи пользователя")
 
-	if err = db.PostgresClient.Create(&user).Error; err != nil {
-		logging.Log.WithFields(logrus.Fields{
+	if err = h.DB.Create(&user).Error; err != nil {
+		if isPassportConflict(err) {
+			h.Log.Errorf("Паспорт уже принадлежит другому пользователю: %v", err)
+			http.Error(w, validation.ErrPassportTaken.Error(), 409)
+			return
+		}
+		h.Log.WithFields(logrus.Fields{
 			"errors": err,
 		}).Error("Неудалось создать пользователя")
 		http.Error(w, fmt.Sprintf("Неудалось создать пользователя: %v", err), 500)
@@ -81,7 +114,7 @@ func CreateUser(w http.ResponseWriter, r *http.Request) {
 
 	json.NewEncoder(w).Encode(map[string]string{"user_id": user.ID.String(), "msg": "Создание пользователя прошло успешно"})
 
-	logging.Log.Info("Запрос на создание пользователя успешно завершен")
+	h.Log.Info("Запрос на создание пользователя успешно завершен")
 
 	return
 }
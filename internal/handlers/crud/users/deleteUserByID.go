@@ -2,33 +2,70 @@ package users
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"gorm.io/gorm"
 	"net/http"
-	"test/internal/db"
-	"test/internal/logging"
+	"strconv"
 	"test/internal/models"
+	"test/internal/timetracking"
 )
 
-func DeleteUserByID(w http.ResponseWriter, r *http.Request) {
-	logging.Log.Info("Запрос на удаление пользователя по ID")
+func (h *UserHandler) DeleteUserByID(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на удаление пользователя по ID")
 
 	vars := mux.Vars(r)
-	id := vars["id"]
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID пользователя: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID пользователя: %v", err), 400)
+		return
+	}
+
+	h.Log.Debugf("ID пользователя на удаление %v", id)
+
+	if err = h.DB.First(&models.Users{}, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			h.Log.Errorf("Пользователь не найден: %v", err)
+			http.Error(w, "Пользователь не найден", 404)
+			return
+		}
+		h.Log.Errorf("Не удалось получить данные пользователя %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось получить данные пользователя: %v", err), 500)
+		return
+	}
+
+	// Пользователь удаляется мягко. Его связи с задачами помечаются удаленными тем же временем,
+	// чтобы при восстановлении вернуть именно их, а запущенные им таймеры останавливаются с паузой задачи
+	now := h.Clock.Now()
 
-	logging.Log.Debugf("ID пользователя на удаление %v", id)
+	var rowsAffected int64
+	err = h.DB.WithContext(r.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := timetracking.UnassignAll(tx, id, now); err != nil {
+			return err
+		}
 
-	if err := db.PostgresClient.Where("ID = ?", id).Delete(&models.Users{}).Error; err != nil {
-		logging.Log.Errorf("Не удалось удалить пользователя %v", err)
-		http.Error(w, fmt.Sprintf("Не удалось удалить пользователя: %v", err), 400)
+		result := tx.Model(&models.Users{}).Where("id = ?", id).Update("deleted_at", now)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		h.Log.Errorf("Не удалось удалить пользователя %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось удалить пользователя: %v", err), 500)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 
-	json.NewEncoder(w).Encode(map[string]string{"user_id": id, "msg": "Удаление пользователя прошло успешно"})
+	json.NewEncoder(w).Encode(map[string]string{
+		"user_id":       id.String(),
+		"rows_affected": strconv.FormatInt(rowsAffected, 10),
+		"msg":           "Удаление пользователя прошло успешно",
+	})
 
-	logging.Log.Info("Запрос на удаление пользователя по ID успешно завершён")
+	h.Log.Info("Запрос на удаление пользователя по ID успешно завершён")
 
 	return
 }
@@ -0,0 +1,41 @@
+package users
+
+import (
+	"context"
+	"strings"
+	"test/internal/models"
+)
+
+// Режим обогащения: вместо серии и номера передан только passportNumber вида "1234 567890"
+func isEnrichRequest(user *models.Users) bool {
+	return user.PassportSerie == "" && len(strings.Fields(user.PassportNumber)) == 2
+}
+
+// enrichUser разбирает паспорт и заполняет незаданные ФИО и адрес данными внешнего сервиса
+func (h *UserHandler) enrichUser(ctx context.Context, user *models.Users) error {
+	passport := strings.Fields(user.PassportNumber)
+	user.PassportSerie, user.PassportNumber = passport[0], passport[1]
+
+	people, err := h.PeopleInfo.Info(ctx, user.PassportSerie, user.PassportNumber)
+	if err != nil {
+		return err
+	}
+
+	h.Log.Debug("Получены данные пользователя по паспорту")
+
+	// Пустое поле в ответе сервиса считается незаданным: пользователь остается без него, как если бы клиент его не передал
+	if user.Name == "" {
+		user.Name = strings.TrimSpace(people.Name)
+	}
+	if user.Surname == "" {
+		user.Surname = strings.TrimSpace(people.Surname)
+	}
+	if user.Patronymic == "" {
+		user.Patronymic = strings.TrimSpace(people.Patronymic)
+	}
+	if user.Address == "" {
+		user.Address = strings.TrimSpace(people.Address)
+	}
+
+	return nil
+}
@@ -2,31 +2,43 @@ package users
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 	"net/http"
-	"test/internal/db"
-	"test/internal/logging"
+	"test/internal/auth"
 	"test/internal/models"
 )
 
-func GetUserByID(w http.ResponseWriter, r *http.Request) {
-	logging.Log.Info("Запрос на получение пользователя по ID")
+func (h *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на получение пользователя по ID")
 
 	vars := mux.Vars(r)
-	id := vars["id"]
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID пользователя: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID пользователя: %v", err), 400)
+		return
+	}
 
-	logging.Log.Debugf("ID пользователя на получение %v", id)
+	h.Log.Debugf("ID пользователя на получение %v", id)
 
 	var resultUser models.Users
-	if err := db.PostgresClient.Where("ID = ?", id).Find(&resultUser).Error; err != nil {
-		logging.Log.Errorf("Не удалось получить данные пользователя %v", err)
-		http.Error(w, fmt.Sprintf("Не удалось получить данные пользователя: %v", err), 400)
+	if err = h.DB.First(&resultUser, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			h.Log.Errorf("Пользователь не найден: %v", err)
+			http.Error(w, "Пользователь не найден", 404)
+			return
+		}
+		h.Log.Errorf("Не удалось получить данные пользователя %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось получить данные пользователя: %v", err), 500)
 		return
 	}
 
-	logging.Log.WithFields(logrus.Fields{
+	h.Log.WithFields(logrus.Fields{
 		"user_id":             resultUser.ID,
 		"user_name":           resultUser.Name,
 		"user_surname":        resultUser.Surname,
@@ -39,6 +51,11 @@ func GetUserByID(w http.ResponseWriter, r *http.Request) {
 		"user_updatedAt":      resultUser.UpdatedAt,
 	}).Debug("Получен пользователь со следующими данными")
 
+	// Паспортные данные по умолчанию отдаются замаскированными, раскрыть их может только администратор
+	if !auth.PassportRevealRequested(r) {
+		resultUser = resultUser.Masked()
+	}
+
 	w.WriteHeader(http.StatusOK)
 
 	json.NewEncoder(w).Encode(map[string]string{
@@ -54,7 +71,7 @@ func GetUserByID(w http.ResponseWriter, r *http.Request) {
 		"UpdatedAt":      resultUser.UpdatedAt.String(),
 	})
 
-	logging.Log.Info("Запрос на получение пользователя по ID успешно завершён")
+	h.Log.Info("Запрос на получение пользователя по ID успешно завершён")
 
 	return
 }
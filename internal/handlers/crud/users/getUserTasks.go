@@ -0,0 +1,107 @@
+package users
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"test/internal/models"
+	"test/internal/timetracking"
+	"time"
+)
+
+// UserTaskResponse - задача с временем, отработанным по ней этим пользователем, без времени других исполнителей
+type UserTaskResponse struct {
+	TaskID       uuid.UUID        `json:"taskID"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description"`
+	State        models.TaskState `json:"state"`
+	Hours        int              `json:"hours"`
+	Minutes      int              `json:"minutes"`
+	Seconds      int              `json:"seconds"`
+	RunningSince *time.Time       `json:"runningSince,omitempty"`
+}
+
+func (h *UserHandler) GetUserTasks(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на получение задач пользователя")
+
+	vars := mux.Vars(r)
+	user_id, err := uuid.Parse(vars["user_id"])
+	if err != nil {
+		h.Log.WithFields(logrus.Fields{
+			"errors": err,
+		}).Error("Некорректный ID пользователя")
+		http.Error(w, fmt.Sprintf("Некорректный ID пользователя: %v", err), 400)
+		return
+	}
+
+	h.Log.Debugf("ID пользователя, для которого будут получены задачи %v", user_id)
+
+	var user models.Users
+	if err = h.DB.First(&user, "id = ?", user_id).Error; err != nil {
+		h.Log.Errorf("Пользователь не найден: %v", err)
+		http.Error(w, "Пользователь не найден", 404)
+		return
+	}
+
+	var tasks []models.Tasks
+	err = h.DB.Model(&models.Tasks{}).
+		Joins("JOIN users_tasks ON users_tasks.task_id = tasks.id::text AND users_tasks.deleted_at IS NULL").
+		Where("users_tasks.user_id = ?", user_id).
+		Order("tasks.created_at DESC").
+		Find(&tasks).Error
+	if err != nil {
+		h.Log.Errorf("Не удалось получить задачи пользователя %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось получить задачи пользователя: %v", err), 500)
+		return
+	}
+
+	h.Log.Debugf("Получен список задач пользователя: %v", tasks)
+
+	taskIDs := []uuid.UUID{}
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+
+	var entries []models.TimeEntries
+	if err = h.DB.Where("task_id IN (?) AND user_id = ?", taskIDs, user_id).Find(&entries).Error; err != nil {
+		h.Log.Errorf("Не удалось получить сессии задач %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось получить сессии задач: %v", err), 500)
+		return
+	}
+
+	// Время запущенного таймера считаем на момент запроса
+	now := h.Clock.Now()
+	durations := map[uuid.UUID]time.Duration{}
+	runningSince := map[uuid.UUID]*time.Time{}
+	for index, entry := range entries {
+		durations[entry.TaskID] += timetracking.EntryDuration(entry, now)
+		if entry.EndTime == nil {
+			runningSince[entry.TaskID] = &entries[index].StartTime
+		}
+	}
+
+	taskResponse := []UserTaskResponse{}
+	for _, task := range tasks {
+		response := UserTaskResponse{
+			TaskID:       task.ID,
+			Name:         task.Name,
+			Description:  task.Description,
+			State:        task.State,
+			RunningSince: runningSince[task.ID],
+		}
+		response.Hours, response.Minutes, response.Seconds = timetracking.SplitDuration(durations[task.ID])
+
+		taskResponse = append(taskResponse, response)
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(taskResponse)
+
+	h.Log.Info("Запрос на получение задач пользователя успешно завершен")
+
+	return
+}
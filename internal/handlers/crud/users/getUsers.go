@@ -2,107 +2,150 @@ package users
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"io"
 	"net/http"
-	"test/internal/db"
-	"test/internal/logging"
+	"strings"
+	"test/internal/auth"
+	"test/internal/filter"
 	"test/internal/models"
+	"test/internal/pagination"
+	"time"
 )
 
-func GetUsers(w http.ResponseWriter, r *http.Request) {
-	logging.Log.Info("Запрос на получение списка пользователей")
+func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на получение списка пользователей")
 
 	// Извлекаем параметры фильтрации из запроса, если они есть
 	input := models.UserGetListInput{}
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && err != io.EOF {
-		logging.Log.Errorf("Ошибка при декодировании параметров фильтрации: %v", err)
+		h.Log.Errorf("Ошибка при декодировании параметров фильтрации: %v", err)
 		http.Error(w, fmt.Sprintf("Ошибка при декодировании параметров фильтрации: %v", err), 400)
 		return
 	}
 
-	logging.Log.WithFields(logrus.Fields{
-		"page":    input.Page,
-		"limit":   input.Limit,
-		"filters": input.Filters,
+	// Текст поиска и значения фильтров - это ФИО и адреса, поэтому в лог пишется только их наличие и поля
+	h.Log.WithFields(logrus.Fields{
+		"search":     input.Q != "",
+		"page":       input.Page,
+		"limit":      input.Limit,
+		"filters":    filter.UserFilterSummary(input.Filters),
+		"sort":       input.Sort,
+		"pagination": input.Pagination,
+		"cursor":     input.Cursor,
 	}).Debug("Получен Input со следующими данными")
 
-	page := 1
-	limit := 10
+	params, err := pagination.Parse(input.Pagination, input.Cursor, input.Page, input.Limit)
+	if err != nil {
+		h.Log.Errorf("Некорректные параметры пагинации: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	// Курсор привязан к порядку (created_at, id), поэтому своя сортировка и ранжирование поиска с ним несовместимы
+	if params.Cursor && len(input.Sort) > 0 {
+		h.Log.Error("Сортировка недоступна в режиме курсора")
+		http.Error(w, "Сортировка недоступна в режиме курсора", 400)
+		return
+	}
+	search := strings.TrimSpace(input.Q)
+	if params.Cursor && search != "" {
+		h.Log.Error("Поиск недоступен в режиме курсора")
+		http.Error(w, "Поиск недоступен в режиме курсора", 400)
+		return
+	}
+
+	h.Log.Debugf("Page=%d, Limit=%d, Offset=%d, Cursor=%v", params.Page, params.Limit, params.Offset(), params.Cursor)
 
-	if input.Page > 0 {
-		page = input.Page
+	query := h.DB.Model(&models.Users{})
+	if input.IncludeDeleted {
+		query = query.Unscoped()
+	}
+	identity, _ := auth.FromContext(r.Context())
+	query = auth.ScopeUsers(query, identity)
+
+	query, err = filter.ApplyUserFilters(query, input.Filters)
+	var filterErr *filter.Error
+	if errors.As(err, &filterErr) {
+		h.Log.Errorf("Некорректные параметры фильтрации: %v", err)
+		filter.WriteError(w, filterErr)
+		return
 	}
 
-	if input.Limit > 0 {
-		limit = input.Limit
+	if search != "" {
+		query = query.Where(models.UsersSearchVector+" @@ plainto_tsquery('russian', ?)", search)
 	}
 
-	offset := (page - 1) * limit
-
-	logging.Log.Debugf("Page=%d, Limit=%d, Offset=%d", page, limit, offset)
-
-	query := db.PostgresClient.Model(&models.Users{})
-
-	if len(input.Filters.Filters) > 0 {
-		for _, filter := range input.Filters.Filters {
-			switch filter.Operator {
-			case "equals":
-				if filter.Value != "" {
-					query = applyFilter(query, filter.Field, filter.Value, filter.Operator)
-				}
-			case "contains":
-				if filter.Value != "" {
-					query = applyFilter(query, filter.Field, filter.Value, filter.Operator)
-				}
-			case "startsWith":
-				if filter.Value != "" {
-					query = applyFilter(query, filter.Field, filter.Value, filter.Operator)
-				}
-			case "endsWith":
-				if filter.Value != "" {
-					query = applyFilter(query, filter.Field, filter.Value, filter.Operator)
-				}
-			default:
-				logging.Log.Warnf("Некорректный оператор фильтрации: %s для поля: %s", filter.Operator, filter.Field)
-			}
+	var total int64
+	if !params.Cursor {
+		// Сессия нужна, чтобы подсчет общего количества и выборка страницы не мешали друг другу
+		query = query.Session(&gorm.Session{})
+
+		if err = query.Count(&total).Error; err != nil {
+			h.Log.Errorf("Не удалось посчитать количество пользователей %v", err)
+			http.Error(w, fmt.Sprintf("Не удалось посчитать количество пользователей: %v", err), 400)
+			return
 		}
 	}
 
-	query = query.Limit(limit).Offset(offset)
+	query, err = params.Window(query, func(query *gorm.DB) (*gorm.DB, error) {
+		// Без явной сортировки найденные поиском пользователи идут по убыванию релевантности
+		if search != "" && len(input.Sort) == 0 {
+			query = query.Order(clause.OrderBy{Expression: clause.Expr{
+				SQL:  "ts_rank(" + models.UsersSearchVector + ", plainto_tsquery('russian', ?)) DESC",
+				Vars: []interface{}{search},
+			}})
+		}
+
+		sorts := []filter.Sort{}
+		for _, userSort := range input.Sort {
+			sorts = append(sorts, filter.Sort{Field: userSort.Field, Direction: userSort.Direction})
+		}
 
-	var users []models.Users
-	if err := query.Find(&users).Error; err != nil {
-		logging.Log.Errorf("Не удалось получить список пользователей %v", err)
+		return filter.UserSchema.Order(query, sorts, pagination.DefaultOrder)
+	})
+	if errors.As(err, &filterErr) {
+		h.Log.Errorf("Некорректные параметры сортировки: %v", err)
+		filter.WriteError(w, filterErr)
+		return
+	}
+	if err != nil {
+		h.Log.Errorf("Некорректные параметры пагинации: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	users := []models.Users{}
+	if err = query.Find(&users).Error; err != nil {
+		h.Log.Errorf("Не удалось получить список пользователей %v", err)
 		http.Error(w, fmt.Sprintf("Не удалось получить список пользователей: %v", err), 400)
 		return
 	}
 
-	logging.Log.Debugf("Получены следующие пользователи: \n %v", users)
+	users, envelope := pagination.Result(params, users, total, func(user models.Users) (time.Time, uuid.UUID) {
+		return user.CreatedAt, user.ID
+	})
+
+	// Паспортные данные по умолчанию отдаются замаскированными, раскрыть их может только администратор
+	if !auth.PassportRevealRequested(r) {
+		for i := range users {
+			users[i] = users[i].Masked()
+		}
+	}
+	response := models.UserListResponse{Items: users, Envelope: envelope}
+
+	// Сами записи не логируются: в них ФИО, адреса, а с reveal=true и паспорта
+	h.Log.Debugf("Получено пользователей: %d", len(users))
 
 	w.WriteHeader(http.StatusOK)
 
-	json.NewEncoder(w).Encode(users)
+	json.NewEncoder(w).Encode(response)
 
-	logging.Log.Info("Запрос на получение списка пользователей успешно выполнен")
+	h.Log.Info("Запрос на получение списка пользователей успешно выполнен")
 
 	return
 }
-
-func applyFilter(query *gorm.DB, field, value, operator string) *gorm.DB {
-	switch operator {
-	case "equals":
-		return query.Where(fmt.Sprintf("%s = ?", field), value)
-	case "contains":
-		return query.Where(fmt.Sprintf("%s LIKE ?", field), "%"+value+"%")
-	case "startsWith":
-		return query.Where(fmt.Sprintf("%s LIKE ?", field), value+"%")
-	case "endsWith":
-		return query.Where(fmt.Sprintf("%s LIKE ?", field), "%"+value)
-	default:
-
-		return query
-	}
-}
@@ -0,0 +1,24 @@
+package users
+
+import (
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"test/internal/clock"
+	"test/internal/peopleinfo"
+	"test/internal/pii"
+	"test/internal/validation"
+)
+
+// UserHandler обрабатывает запросы /users. Все зависимости передаются при создании, чтобы в тестах их можно было подменить
+type UserHandler struct {
+	DB     *gorm.DB
+	Log    logrus.FieldLogger
+	Clock  clock.Clock
+	Cipher *pii.Cipher
+	// Может быть nil, тогда создание пользователя только по паспорту недоступно
+	PeopleInfo *peopleinfo.Client
+}
+
+func (h *UserHandler) validator() *validation.Validator {
+	return &validation.Validator{DB: h.DB, Log: h.Log, Cipher: h.Cipher}
+}
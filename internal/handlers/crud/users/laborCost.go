@@ -7,124 +7,84 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 	"net/http"
-	"sort"
-	"test/internal/db"
-	"test/internal/logging"
+	"test/internal/laborcost"
 	"test/internal/models"
-	"time"
 )
 
-type TaskResponse struct {
-	TaskID  uuid.UUID `json:"taskID"`
-	Name    string    `json:"name"`
-	Hours   int       `json:"hours"`
-	Minutes int       `json:"minutes"`
-	Seconds int       `json:"seconds"`
-}
-
-type Period struct {
-	StartTime time.Time `json:"start_time"`
-	EndTime   time.Time `json:"end_time"`
-}
-
-type ByDuration []models.Tasks
+func (h *UserHandler) LaborCost(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на получение трудозатрат пользователя")
 
-func (a ByDuration) Len() int      { return len(a) }
-func (a ByDuration) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
-func (a ByDuration) Less(i, j int) bool {
-	if a[i].Hours != a[j].Hours {
-		return a[i].Hours > a[j].Hours
-	}
-	if a[i].Minutes != a[j].Minutes {
-		return a[i].Minutes > a[j].Minutes
+	vars := mux.Vars(r)
+	user_id, err := uuid.Parse(vars["user_id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID пользователя: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID пользователя: %v", err), 400)
+		return
 	}
-	return a[i].Seconds > a[j].Seconds
-}
 
-func LaborCost(w http.ResponseWriter, r *http.Request) {
-	logging.Log.Info("Запрос на получение трудозатрат пользователя")
+	h.Log.Debugf("ID пользователя, для которого будут получены трудозатраты %v", user_id)
 
-	vars := mux.Vars(r)
-	user_id := vars["user_id"]
+	// Трудозатраты удаленного пользователя тоже можно получить
+	if err = h.DB.Unscoped().First(&models.Users{}, "id = ?", user_id).Error; err != nil {
+		h.Log.Errorf("Пользователь не найден: %v", err)
+		http.Error(w, "Пользователь не найден", 404)
+		return
+	}
 
-	logging.Log.Debugf("ID пользователя, для которого будут получены трудозатраты %v", user_id)
+	// Формат ответа: JSON по умолчанию, CSV или XLSX по параметру format или заголовку Accept
+	format, err := laborcost.RequestFormat(r)
+	if err != nil {
+		h.Log.Errorf("Некорректный формат отчета: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
 
 	// Получаем параметры периода из тела запроса
-	var period Period
-	if err := json.NewDecoder(r.Body).Decode(&period); err != nil {
-		logging.Log.Errorf("Не удалось декодировать параметры периода: %v", err)
+	var input laborcost.Input
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.Log.Errorf("Не удалось декодировать параметры периода: %v", err)
 		http.Error(w, fmt.Sprintf("Не удалось декодировать параметры периода: %v", err), 400)
 		return
 	}
 
-	logging.Log.Debugf("Период времени: %v - %v", period.StartTime, period.EndTime)
+	h.Log.WithFields(logrus.Fields{
+		"start_time":      input.StartTime,
+		"end_time":        input.EndTime,
+		"include_running": input.IncludeRunning,
+		"group_by":        input.GroupBy,
+	}).Debug("Параметры расчета трудозатрат")
 
-	//Получаем ID задач назначенных на пользователя
-	var usersTasks []models.UsersTasks
-	if err := db.PostgresClient.Where("user_id = ?", user_id).Find(&usersTasks).Error; err != nil {
-		logging.Log.Errorf("Не удалось получить задачи пользователя %v", err)
-		http.Error(w, fmt.Sprintf("Не удалось получить задачи пользователя: %v", err), 400)
+	if err = input.Validate(); err != nil {
+		h.Log.Errorf("Некорректные параметры расчета трудозатрат: %v", err)
+		http.Error(w, err.Error(), 400)
 		return
 	}
 
-	logging.Log.Debugf("Получен список отношений пользователя и задач: %v", usersTasks)
-
-	//Получаем полные данные этих задач за период
-	taskIDs := []uuid.UUID{}
-	for _, taskRelation := range usersTasks {
-		taskIDs = append(taskIDs, taskRelation.TaskID)
-	}
-
-	var tasks []models.Tasks
-	if err := db.PostgresClient.Where("ID IN (?) AND start_time >= ? AND end_time <= ?", taskIDs, period.StartTime, period.EndTime).Find(&tasks).Error; err != nil {
-		logging.Log.Errorf("Не удалось получить задачи: %v", err)
-		http.Error(w, fmt.Sprintf("Не удалось получить задачи: %v", err), 400)
+	report, err := laborcost.UserReport(h.DB.WithContext(r.Context()), user_id, input, h.Clock.Now())
+	if err != nil {
+		h.Log.Errorf("Не удалось получить трудозатраты пользователя: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось получить трудозатраты пользователя: %v", err), 400)
 		return
 	}
 
-	logging.Log.Debugf("Получен список задач: %v", tasks)
-
-	for index := range tasks {
-		calculateTaskDuration(&tasks[index])
-		logging.Log.WithFields(logrus.Fields{
-			"TaskID":  tasks[index].ID,
-			"Hours":   tasks[index].Hours,
-			"Minutes": tasks[index].Minutes,
-			"Seconds": tasks[index].Seconds,
-		}).Debugf("Получена трудозатрата для пользователя с ID:  %v", user_id)
-	}
-	//Сортируем от большей к меньшей
-	sort.Sort(ByDuration(tasks))
-
-	// Создаем массив для ответа
-	taskResponse := []TaskResponse{}
-	for _, task := range tasks {
-		taskResponse = append(taskResponse, TaskResponse{
-			TaskID:  task.ID,
-			Name:    task.Name,
-			Hours:   task.Hours,
-			Minutes: task.Minutes,
-			Seconds: task.Seconds,
-		})
-	}
+	h.Log.Debugf("Получены трудозатраты пользователя с ID %v: %v", user_id, report)
 
-	w.WriteHeader(http.StatusOK)
+	if format != laborcost.FormatJSON {
+		if err = laborcost.WriteTable(w, format, fmt.Sprintf("laborCost_%s", user_id), report.Table()); err != nil {
+			h.Log.Errorf("Не удалось выгрузить отчет: %v", err)
+			http.Error(w, fmt.Sprintf("Не удалось выгрузить отчет: %v", err), 500)
+			return
+		}
 
-	json.NewEncoder(w).Encode(taskResponse)
+		h.Log.Infof("Отчет выгружен в формате %s", format)
 
-	logging.Log.Info("Запрос на получение трудозатрат пользователя успешно завершен")
+		return
+	}
 
-}
+	w.WriteHeader(http.StatusOK)
 
-func calculateTaskDuration(task *models.Tasks) {
-	if task.StartTime != nil && task.EndTime != nil {
-		duration := task.EndTime.Sub(*task.StartTime)
-		totalSeconds := int(duration.Seconds())
+	json.NewEncoder(w).Encode(report)
 
-		task.Hours = totalSeconds / 3600
-		totalSeconds %= 3600
+	h.Log.Info("Запрос на получение трудозатрат пользователя успешно завершен")
 
-		task.Minutes = totalSeconds / 60
-		task.Seconds = totalSeconds % 60
-	}
 }
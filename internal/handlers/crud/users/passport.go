@@ -0,0 +1,12 @@
+package users
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"test/internal/validation"
+)
+
+// Паспорт занят другим пользователем: это нашла проверка перед записью или уникальный индекс БД
+func isPassportConflict(err error) bool {
+	return errors.Is(err, validation.ErrPassportTaken) || errors.Is(err, gorm.ErrDuplicatedKey)
+}
@@ -0,0 +1,96 @@
+package users
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+	"test/internal/models"
+	"test/internal/validation"
+)
+
+func (h *UserHandler) PatchUserByID(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на частичное обновление данных пользователя")
+
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID пользователя: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID пользователя: %v", err), 400)
+		return
+	}
+
+	h.Log.Debugf("ID пользователя на частичное обновление данных %v", id)
+
+	var input models.UsersPatchInput
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.Log.WithFields(logrus.Fields{
+			"errors": err,
+		}).Error("Не удалось декодировать тело запроса в структуру UsersPatchInput")
+		http.Error(w, fmt.Sprintf("Не удалось декодировать тело запроса: %v", err), 400)
+		return
+	}
+
+	var user models.Users
+	if err = h.DB.First(&user, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			h.Log.Errorf("Пользователь не найден: %v", err)
+			http.Error(w, "Пользователь не найден", 404)
+			return
+		}
+		h.Log.Errorf("Не удалось получить данные пользователя %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось получить данные пользователя: %v", err), 500)
+		return
+	}
+
+	columns, err := h.validator().ValidatePatchUser(&user, &input)
+	if err != nil {
+		if isPassportConflict(err) {
+			h.Log.Errorf("Паспорт уже принадлежит другому пользователю: %v", err)
+			http.Error(w, validation.ErrPassportTaken.Error(), 409)
+			return
+		}
+		h.Log.WithFields(logrus.Fields{
+			"errors": err,
+		}).Error("Данные пользователя не прошли валидацию")
+		http.Error(w, fmt.Sprintf("Данные пользователя не прошли валидацию: %v", err), 400)
+		return
+	}
+	if len(columns) == 0 {
+		h.Log.Error("Не передано ни одного поля для обновления")
+		http.Error(w, "Не передано ни одного поля для обновления", 400)
+		return
+	}
+
+	h.Log.Debugf("Колонки для частичного обновления записи пользователя с ID %v: %v", id, columns)
+
+	// Обновление через структуру, а не map, чтобы паспортные поля прошли через шифрование
+	result := h.DB.Model(&user).Select(columns).Updates(&user)
+	if result.Error != nil {
+		if isPassportConflict(result.Error) {
+			h.Log.Errorf("Паспорт уже принадлежит другому пользователю: %v", result.Error)
+			http.Error(w, validation.ErrPassportTaken.Error(), 409)
+			return
+		}
+		h.Log.Errorf("Не удалось обновить данные пользователя %v", result.Error)
+		http.Error(w, fmt.Sprintf("Не удалось обновить данные пользователя: %v", result.Error), 400)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"user_id":       id.String(),
+		"rows_affected": strconv.FormatInt(result.RowsAffected, 10),
+		"msg":           "Обновление данных пользователя прошло успешно",
+	})
+
+	h.Log.Info("Запрос на частичное обновление данных пользователя успешно завершен")
+
+	return
+}
@@ -0,0 +1,79 @@
+package users
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+	"test/internal/models"
+)
+
+func (h *UserHandler) RestoreUserByID(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на восстановление пользователя по ID")
+
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID пользователя: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID пользователя: %v", err), 400)
+		return
+	}
+
+	h.Log.Debugf("ID пользователя на восстановление %v", id)
+
+	var user models.Users
+	if err = h.DB.Unscoped().First(&user, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			h.Log.Errorf("Пользователь не найден: %v", err)
+			http.Error(w, "Пользователь не найден", 404)
+			return
+		}
+		h.Log.Errorf("Не удалось получить данные пользователя %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось получить данные пользователя: %v", err), 500)
+		return
+	}
+
+	if !user.DeletedAt.Valid {
+		h.Log.Errorf("Пользователь %v не удален", id)
+		http.Error(w, "Пользователь не удален", 409)
+		return
+	}
+
+	// Возвращаем только связи, снятые вместе с удалением пользователя.
+	// Задачи, поставленные тогда на паузу, остаются на паузе
+	var linksRestored int64
+	err = h.DB.WithContext(r.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&user).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Model(&models.UsersTasks{}).
+			Where("user_id = ? AND deleted_at = ?", id, user.DeletedAt.Time).
+			Update("deleted_at", nil)
+		linksRestored = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		h.Log.Errorf("Не удалось восстановить пользователя %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось восстановить пользователя: %v", err), 500)
+		return
+	}
+
+	h.Log.Debugf("Восстановлено связей пользователя %v с задачами: %d", id, linksRestored)
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"user_id":        id.String(),
+		"tasks_restored": strconv.FormatInt(linksRestored, 10),
+		"msg":            "Восстановление пользователя прошло успешно",
+	})
+
+	h.Log.Info("Запрос на восстановление пользователя по ID успешно завершён")
+
+	return
+}
@@ -0,0 +1,67 @@
+package users
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"net/http"
+	"test/internal/auth"
+	"test/internal/models"
+)
+
+func (h *UserHandler) SetPassword(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на установку пароля пользователя")
+
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID пользователя: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID пользователя: %v", err), 400)
+		return
+	}
+
+	h.Log.Debugf("ID пользователя для установки пароля %v", id)
+
+	var input models.UsersPasswordInput
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.Log.Errorf("Не удалось декодировать тело запроса: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось декодировать тело запроса: %v", err), 400)
+		return
+	}
+
+	var user models.Users
+	if err = h.DB.First(&user, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			h.Log.Errorf("Пользователь не найден: %v", err)
+			http.Error(w, "Пользователь не найден", 404)
+			return
+		}
+		h.Log.Errorf("Не удалось получить данные пользователя %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось получить данные пользователя: %v", err), 500)
+		return
+	}
+
+	hash, err := auth.HashPassword(input.Password)
+	if err != nil {
+		h.Log.Errorf("Пароль не прошел проверку: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	if err = h.DB.Model(&user).Update("password_hash", hash).Error; err != nil {
+		h.Log.Errorf("Не удалось сохранить пароль пользователя %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось сохранить пароль пользователя: %v", err), 500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(map[string]string{"user_id": id.String(), "msg": "Пароль пользователя установлен"})
+
+	h.Log.Info("Запрос на установку пароля пользователя успешно завершен")
+
+	return
+}
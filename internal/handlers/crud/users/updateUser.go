@@ -3,27 +3,32 @@ package users
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 	"io"
 	"net/http"
-	"test/internal/db"
-	"test/internal/logging"
+	"strconv"
 	"test/internal/models"
 	"test/internal/validation"
 )
 
-func UpdateUserByID(w http.ResponseWriter, r *http.Request) {
-	logging.Log.Info("Запрос на обновление данных пользователя")
+func (h *UserHandler) UpdateUserByID(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на обновление данных пользователя")
 
 	vars := mux.Vars(r)
-	id := vars["id"]
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.Log.Errorf("Некорректный ID пользователя: %v", err)
+		http.Error(w, fmt.Sprintf("Некорректный ID пользователя: %v", err), 400)
+		return
+	}
 
-	logging.Log.Debugf("ID пользователя на обновление данных %v", id)
+	h.Log.Debugf("ID пользователя на обновление данных %v", id)
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		logging.Log.WithFields(logrus.Fields{
+		h.Log.WithFields(logrus.Fields{
 			"errors": err,
 		}).Error("Не удалось прочитать тело запроса")
 		http.Error(w, fmt.Sprintf("Не удалось прочитать тело запроса: %v", err), 400)
@@ -32,24 +37,31 @@ func UpdateUserByID(w http.ResponseWriter, r *http.Request) {
 
 	var user models.Users
 	if err = json.Unmarshal(body, &user); err != nil {
-		logging.Log.WithFields(logrus.Fields{
+		h.Log.WithFields(logrus.Fields{
 			"errors": err,
 		}).Error("Не удалось декодировать тело запроса в структуру Users")
 		http.Error(w, fmt.Sprintf("Не удалось удалось декодировать тело запроса в структуру Users: %v", err), 400)
 		return
 	}
 
+	user.ID = id
 	user.FullPassport = user.PassportSerie + user.PassportNumber
+	user.PassportHash = h.Cipher.BlindIndex(user.FullPassport)
 
-	if err = validation.ValidateUpdateUser(&user); err != nil {
-		logging.Log.WithFields(logrus.Fields{
+	if err = h.validator().ValidateUpdateUser(&user); err != nil {
+		if isPassportConflict(err) {
+			h.Log.Errorf("Паспорт уже принадлежит другому пользователю: %v", err)
+			http.Error(w, validation.ErrPassportTaken.Error(), 409)
+			return
+		}
+		h.Log.WithFields(logrus.Fields{
 			"errors": err,
 		}).Error("Данные пользователя не прошли валидацию")
 		http.Error(w, fmt.Sprintf("Данные пользователя не прошли валидацию: %v", err), 400)
 		return
 	}
 
-	logging.Log.WithFields(logrus.Fields{
+	h.Log.WithFields(logrus.Fields{
 		"user_id":             user.ID,
 		"user_name":           user.Name,
 		"user_surname":        user.Surname,
@@ -62,17 +74,32 @@ func UpdateUserByID(w http.ResponseWriter, r *http.Request) {
 		"user_updatedAt":      user.UpdatedAt,
 	}).Debugf("Данные для обновления записи пользователя с ID: %v", id)
 
-	if err = db.PostgresClient.Where("ID = ?", id).Updates(user).Error; err != nil {
-		logging.Log.Errorf("Не удалось обновить данные пользователя %v", err)
-		http.Error(w, fmt.Sprintf("Не удалось обновить данные пользователя: %v", err), 400)
+	result := h.DB.Model(&models.Users{}).Where("id = ?", id).Updates(user)
+	if result.Error != nil {
+		if isPassportConflict(result.Error) {
+			h.Log.Errorf("Паспорт уже принадлежит другому пользователю: %v", result.Error)
+			http.Error(w, validation.ErrPassportTaken.Error(), 409)
+			return
+		}
+		h.Log.Errorf("Не удалось обновить данные пользователя %v", result.Error)
+		http.Error(w, fmt.Sprintf("Не удалось обновить данные пользователя: %v", result.Error), 400)
+		return
+	}
+	if result.RowsAffected == 0 {
+		h.Log.Errorf("Пользователь с ID %v не найден", id)
+		http.Error(w, "Пользователь не найден", 404)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 
-	json.NewEncoder(w).Encode(map[string]string{"user_id": id, "msg": "Обновление данных пользователя прошло успешно"})
+	json.NewEncoder(w).Encode(map[string]string{
+		"user_id":       id.String(),
+		"rows_affected": strconv.FormatInt(result.RowsAffected, 10),
+		"msg":           "Обновление данных пользователя прошло успешно",
+	})
 
-	logging.Log.Info("Запрос на обновление данных пользователя успешно завершен")
+	h.Log.Info("Запрос на обновление данных пользователя успешно завершен")
 
 	return
 }
@@ -0,0 +1,14 @@
+package reports
+
+import (
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"test/internal/clock"
+)
+
+// ReportHandler обрабатывает запросы /reports
+type ReportHandler struct {
+	DB    *gorm.DB
+	Log   logrus.FieldLogger
+	Clock clock.Clock
+}
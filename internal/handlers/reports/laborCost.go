@@ -0,0 +1,94 @@
+package reports
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"test/internal/auth"
+	"test/internal/filter"
+	"test/internal/laborcost"
+	"test/internal/models"
+)
+
+func (h *ReportHandler) LaborCost(w http.ResponseWriter, r *http.Request) {
+	h.Log.Info("Запрос на получение отчета по трудозатратам команды")
+
+	// Формат ответа: JSON по умолчанию, CSV или XLSX по параметру format или заголовку Accept
+	format, err := laborcost.RequestFormat(r)
+	if err != nil {
+		h.Log.Errorf("Некорректный формат отчета: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	var input laborcost.TeamInput
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.Log.Errorf("Не удалось декодировать параметры отчета: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось декодировать параметры отчета: %v", err), 400)
+		return
+	}
+
+	// Значения фильтров - это ФИО и адреса, поэтому в лог пишутся только поля и операторы
+	h.Log.WithFields(logrus.Fields{
+		"start_time":      input.StartTime,
+		"end_time":        input.EndTime,
+		"include_running": input.IncludeRunning,
+		"group_by":        input.GroupBy,
+		"filters":         filter.UserFilterSummary(input.Filters),
+	}).Debug("Параметры отчета по трудозатратам")
+
+	if err = input.Validate(); err != nil {
+		h.Log.Errorf("Некорректные параметры отчета: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	// В отчет попадают все пользователи, подходящие под фильтр, без пагинации
+	// Руководитель получает отчет только по своей команде
+	identity, _ := auth.FromContext(r.Context())
+	query, err := filter.ApplyUserFilters(auth.ScopeUsers(h.DB.Model(&models.Users{}), identity), input.Filters)
+	var filterErr *filter.Error
+	if errors.As(err, &filterErr) {
+		h.Log.Errorf("Некорректные параметры фильтрации: %v", err)
+		filter.WriteError(w, filterErr)
+		return
+	}
+
+	var teamUsers []models.Users
+	if err = query.Order("surname, name, patronymic").Find(&teamUsers).Error; err != nil {
+		h.Log.Errorf("Не удалось получить список пользователей %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось получить список пользователей: %v", err), 400)
+		return
+	}
+
+	h.Log.Debugf("Пользователей в отчете: %d", len(teamUsers))
+
+	team, err := laborcost.TeamReport(h.DB.WithContext(r.Context()), teamUsers, input.Input, h.Clock.Now())
+	if err != nil {
+		h.Log.Errorf("Не удалось получить трудозатраты команды: %v", err)
+		http.Error(w, fmt.Sprintf("Не удалось получить трудозатраты команды: %v", err), 500)
+		return
+	}
+
+	if format != laborcost.FormatJSON {
+		if err = laborcost.WriteTable(w, format, "laborCost_team", team.Table()); err != nil {
+			h.Log.Errorf("Не удалось выгрузить отчет: %v", err)
+			http.Error(w, fmt.Sprintf("Не удалось выгрузить отчет: %v", err), 500)
+			return
+		}
+
+		h.Log.Infof("Отчет выгружен в формате %s", format)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(team)
+
+	h.Log.Info("Запрос на получение отчета по трудозатратам команды успешно завершен")
+
+	return
+}
@@ -0,0 +1,80 @@
+package laborcost
+
+import (
+	"fmt"
+	"sort"
+	"test/internal/models"
+	"test/internal/timetracking"
+	"time"
+)
+
+type Bucket struct {
+	// День в формате 2006-01-02 или ISO-неделя в формате 2006-W01
+	Label     string    `json:"label"`
+	StartTime time.Time `json:"start_time"`
+	Duration
+	Percent float64 `json:"percent"`
+}
+
+// Раскладываем сессии по дням или неделям, разрезая сессии на их границах.
+// Границы считаются в часовом поясе начала периода
+func buckets(entries []models.TimeEntries, input Input, now time.Time) []Bucket {
+	location := input.StartTime.Location()
+
+	bucketStart, days := dayStart, 1
+	if input.GroupBy == GroupByWeek {
+		bucketStart, days = weekStart, 7
+	}
+
+	var total time.Duration
+	durations := map[time.Time]time.Duration{}
+	for _, entry := range entries {
+		from, to, ok := timetracking.ClippedInterval(entry, input.StartTime, input.EndTime, now)
+		if !ok {
+			continue
+		}
+
+		for from.Before(to) {
+			start := bucketStart(from.In(location))
+			segmentEnd := start.AddDate(0, 0, days)
+			if to.Before(segmentEnd) {
+				segmentEnd = to
+			}
+
+			durations[start] += segmentEnd.Sub(from)
+			total += segmentEnd.Sub(from)
+			from = segmentEnd
+		}
+	}
+
+	result := []Bucket{}
+	for start, duration := range durations {
+		result = append(result, Bucket{
+			Label:     bucketLabel(start, input.GroupBy),
+			StartTime: start,
+			Duration:  newDuration(duration),
+			Percent:   percent(duration, total),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].StartTime.Before(result[j].StartTime) })
+
+	return result
+}
+
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// ISO-неделя начинается с понедельника
+func weekStart(t time.Time) time.Time {
+	day := dayStart(t)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+func bucketLabel(start time.Time, groupBy string) string {
+	if groupBy == GroupByWeek {
+		year, week := start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	}
+	return start.Format("2006-01-02")
+}
@@ -0,0 +1,118 @@
+package laborcost
+
+import (
+	"test/internal/models"
+	"testing"
+	"time"
+)
+
+func TestBucketBoundaries(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	tests := []struct {
+		name      string
+		time      time.Time
+		wantDay   time.Time
+		wantWeek  time.Time
+		wantLabel string
+	}{
+		{name: "середина недели", time: time.Date(2024, 7, 3, 15, 30, 0, 0, time.UTC), wantDay: time.Date(2024, 7, 3, 0, 0, 0, 0, time.UTC), wantWeek: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), wantLabel: "2024-W27"},
+		{name: "понедельник", time: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), wantDay: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), wantWeek: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), wantLabel: "2024-W27"},
+		{name: "воскресенье относится к прошлой неделе", time: time.Date(2024, 7, 7, 23, 59, 59, 0, time.UTC), wantDay: time.Date(2024, 7, 7, 0, 0, 0, 0, time.UTC), wantWeek: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), wantLabel: "2024-W27"},
+		{name: "ISO-неделя прошлого года", time: time.Date(2021, 1, 2, 12, 0, 0, 0, time.UTC), wantDay: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC), wantWeek: time.Date(2020, 12, 28, 0, 0, 0, 0, time.UTC), wantLabel: "2020-W53"},
+		{name: "ISO-неделя следующего года", time: time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC), wantDay: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), wantWeek: time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), wantLabel: "2025-W01"},
+		{name: "свой часовой пояс", time: time.Date(2024, 7, 1, 1, 0, 0, 0, moscow), wantDay: time.Date(2024, 7, 1, 0, 0, 0, 0, moscow), wantWeek: time.Date(2024, 7, 1, 0, 0, 0, 0, moscow), wantLabel: "2024-W27"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dayStart(tt.time); !got.Equal(tt.wantDay) {
+				t.Fatalf("начало дня %v, ожидалось %v", got, tt.wantDay)
+			}
+			week := weekStart(tt.time)
+			if !week.Equal(tt.wantWeek) {
+				t.Fatalf("начало недели %v, ожидалось %v", week, tt.wantWeek)
+			}
+			if got := bucketLabel(week, GroupByWeek); got != tt.wantLabel {
+				t.Fatalf("метка недели %s, ожидалась %s", got, tt.wantLabel)
+			}
+			if got, want := bucketLabel(dayStart(tt.time), GroupByDay), tt.wantDay.Format("2006-01-02"); got != want {
+				t.Fatalf("метка дня %s, ожидалась %s", got, want)
+			}
+		})
+	}
+}
+
+func TestBuckets(t *testing.T) {
+	utc := func(day, hour int) time.Time { return time.Date(2024, 7, day, hour, 0, 0, 0, time.UTC) }
+	entry := func(start, end time.Time) models.TimeEntries {
+		return models.TimeEntries{StartTime: start, EndTime: &end}
+	}
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	type want struct {
+		label   string
+		hours   int
+		percent float64
+	}
+
+	tests := []struct {
+		name    string
+		entries []models.TimeEntries
+		input   Input
+		now     time.Time
+		want    []want
+	}{
+		{
+			name:    "сессия через полночь делится по дням",
+			entries: []models.TimeEntries{entry(utc(1, 22), utc(2, 4))},
+			input:   Input{Period: Period{StartTime: utc(1, 0), EndTime: utc(8, 0)}, GroupBy: GroupByDay},
+			want:    []want{{"2024-07-01", 2, 33.33}, {"2024-07-02", 4, 66.67}},
+		},
+		{
+			name:    "сессии обрезаются периодом",
+			entries: []models.TimeEntries{entry(utc(1, 6), utc(1, 12)), entry(utc(3, 20), utc(4, 6))},
+			input:   Input{Period: Period{StartTime: utc(1, 9), EndTime: utc(4, 0)}, GroupBy: GroupByDay},
+			want:    []want{{"2024-07-01", 3, 42.86}, {"2024-07-03", 4, 57.14}},
+		},
+		{
+			name:    "сессия через воскресенье делится по неделям",
+			entries: []models.TimeEntries{entry(utc(7, 20), utc(8, 2)), entry(utc(9, 10), utc(9, 12))},
+			input:   Input{Period: Period{StartTime: utc(1, 0), EndTime: utc(15, 0)}, GroupBy: GroupByWeek},
+			want:    []want{{"2024-W27", 4, 50}, {"2024-W28", 4, 50}},
+		},
+		{
+			name:    "запущенный таймер считается до now",
+			entries: []models.TimeEntries{{StartTime: utc(2, 9)}},
+			input:   Input{Period: Period{StartTime: utc(1, 0), EndTime: utc(8, 0)}, GroupBy: GroupByDay},
+			now:     utc(2, 12),
+			want:    []want{{"2024-07-02", 3, 100}},
+		},
+		{
+			name:    "границы дней в часовом поясе периода",
+			entries: []models.TimeEntries{entry(utc(1, 19), utc(1, 23))},
+			input:   Input{Period: Period{StartTime: time.Date(2024, 7, 1, 0, 0, 0, 0, moscow), EndTime: time.Date(2024, 7, 8, 0, 0, 0, 0, moscow)}, GroupBy: GroupByDay},
+			want:    []want{{"2024-07-01", 2, 50}, {"2024-07-02", 2, 50}},
+		},
+		{
+			name:  "нет сессий",
+			input: Input{Period: Period{StartTime: utc(1, 0), EndTime: utc(8, 0)}, GroupBy: GroupByDay},
+			want:  []want{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buckets(tt.entries, tt.input, tt.now)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("получено %d интервалов, ожидалось %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, w := range tt.want {
+				if got[i].Label != w.label || got[i].Hours != w.hours || got[i].Minutes != 0 || got[i].Percent != w.percent {
+					t.Fatalf("интервал %d: %+v, ожидалось %+v", i, got[i], w)
+				}
+			}
+		})
+	}
+}
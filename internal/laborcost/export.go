@@ -0,0 +1,171 @@
+package laborcost
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+
+	contentTypeCSV  = "text/csv"
+	contentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// Table - отчет в табличном виде для выгрузки в CSV и XLSX
+type Table struct {
+	Header []string
+	Rows   [][]interface{}
+}
+
+// RequestFormat определяет формат ответа по параметру format, а если его нет - по заголовку Accept
+func RequestFormat(r *http.Request) (string, error) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		switch format {
+		case FormatJSON, FormatCSV, FormatXLSX:
+			return format, nil
+		default:
+			return "", fmt.Errorf("Некорректный формат отчета: %s. Допустимые значения: %s, %s, %s", format, FormatJSON, FormatCSV, FormatXLSX)
+		}
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case contentTypeCSV:
+			return FormatCSV, nil
+		case contentTypeXLSX:
+			return FormatXLSX, nil
+		}
+	}
+
+	return FormatJSON, nil
+}
+
+// WriteTable отдает отчет файлом в формате CSV или XLSX
+func WriteTable(w http.ResponseWriter, format, filename string, table Table) error {
+	switch format {
+	case FormatCSV:
+		w.Header().Set("Content-Type", contentTypeCSV+"; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".csv"))
+		w.WriteHeader(http.StatusOK)
+		return writeCSV(w, table)
+	case FormatXLSX:
+		// Книгу собираем целиком до отправки заголовков, чтобы при ошибке можно было ответить 500
+		file, err := buildXLSX(table)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		w.Header().Set("Content-Type", contentTypeXLSX)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".xlsx"))
+		w.WriteHeader(http.StatusOK)
+		return file.Write(w)
+	default:
+		return fmt.Errorf("Формат %s не поддерживает табличную выгрузку", format)
+	}
+}
+
+func writeCSV(w io.Writer, table Table) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(table.Header); err != nil {
+		return err
+	}
+	for _, row := range table.Rows {
+		record := make([]string, len(row))
+		for index, value := range row {
+			record[index] = fmt.Sprint(safeCell(value))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func buildXLSX(table Table) (*excelize.File, error) {
+	file := excelize.NewFile()
+	sheet := file.GetSheetName(0)
+
+	header := make([]interface{}, len(table.Header))
+	for index, value := range table.Header {
+		header[index] = value
+	}
+	if err := file.SetSheetRow(sheet, "A1", &header); err != nil {
+		return nil, err
+	}
+
+	for index, row := range table.Rows {
+		cell, err := excelize.CoordinatesToCellName(1, index+2)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, len(row))
+		for column, value := range row {
+			values[column] = safeCell(value)
+		}
+		if err = file.SetSheetRow(sheet, cell, &values); err != nil {
+			return nil, err
+		}
+	}
+
+	return file, nil
+}
+
+// safeCell экранирует строки, которые табличный редактор принял бы за формулу.
+// Названия задач и ФИО задают пользователи, и без этого в выгрузку можно подложить =HYPERLINK(...)
+func safeCell(value interface{}) interface{} {
+	text, ok := value.(string)
+	if ok && text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return value
+}
+
+var reportHeader = []string{"task_id", "task_name", "hours", "minutes", "seconds", "percent"}
+
+// Table раскладывает отчет пользователя по строкам: одна строка на задачу и итоговая строка
+func (report *Report) Table() Table {
+	table := Table{Header: reportHeader}
+	for _, task := range report.Tasks {
+		table.Rows = append(table.Rows, taskRow(task))
+	}
+	table.Rows = append(table.Rows, totalRow(report.Total))
+	return table
+}
+
+// Table раскладывает отчет команды по строкам: задачи каждого пользователя, итог пользователя и общий итог
+func (team *Team) Table() Table {
+	table := Table{Header: append([]string{"user_id", "surname", "name", "patronymic"}, reportHeader...)}
+	for _, user := range team.Users {
+		userColumns := []interface{}{user.UserID.String(), user.Surname, user.Name, user.Patronymic}
+		for _, task := range user.Tasks {
+			table.Rows = append(table.Rows, append(userColumns, taskRow(task)...))
+		}
+		table.Rows = append(table.Rows, append(userColumns, totalRow(user.Report.Total)...))
+	}
+	table.Rows = append(table.Rows, append([]interface{}{"", "", "", ""}, totalRow(team.Total)...))
+	return table
+}
+
+func taskRow(task TaskResponse) []interface{} {
+	return []interface{}{task.TaskID.String(), task.Name, task.Hours, task.Minutes, task.Seconds, task.Percent}
+}
+
+func totalRow(total Duration) []interface{} {
+	return []interface{}{"", "Итого", total.Hours, total.Minutes, total.Seconds, ""}
+}
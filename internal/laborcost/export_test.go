@@ -0,0 +1,43 @@
+package laborcost
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSafeCell(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  interface{}
+	}{
+		{value: "=HYPERLINK(\"http://evil\")", want: "'=HYPERLINK(\"http://evil\")"},
+		{value: "+1", want: "'+1"},
+		{value: "-1+2", want: "'-1+2"},
+		{value: "@SUM(A1)", want: "'@SUM(A1)"},
+		{value: "\tcmd", want: "'\tcmd"},
+		{value: "\rcmd", want: "'\rcmd"},
+		{value: "Отчет", want: "Отчет"},
+		{value: "", want: ""},
+		{value: -5, want: -5},
+		{value: 12.5, want: 12.5},
+	}
+
+	for _, tt := range tests {
+		if got := safeCell(tt.value); got != tt.want {
+			t.Errorf("safeCell(%q) = %q, ожидалось %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestWriteCSVEscapesFormulas(t *testing.T) {
+	var buffer bytes.Buffer
+	table := Table{Header: []string{"task_name", "hours"}, Rows: [][]interface{}{{"=1+1", 2}}}
+
+	if err := writeCSV(&buffer, table); err != nil {
+		t.Fatalf("не удалось записать CSV: %v", err)
+	}
+	if !strings.Contains(buffer.String(), "'=1+1,2") {
+		t.Fatalf("формула не экранирована: %q", buffer.String())
+	}
+}
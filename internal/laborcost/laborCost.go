@@ -0,0 +1,261 @@
+package laborcost
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"math"
+	"sort"
+	"test/internal/logging"
+	"test/internal/models"
+	"test/internal/timetracking"
+	"time"
+)
+
+const (
+	GroupByDay  = "day"
+	GroupByWeek = "week"
+)
+
+type Period struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+type Input struct {
+	Period
+	// Учитывать запущенные таймеры, считая их время до момента запроса
+	IncludeRunning bool `json:"include_running"`
+	// Разбивка трудозатрат по дням (day) или ISO-неделям (week)
+	GroupBy string `json:"group_by"`
+}
+
+type Duration struct {
+	Hours   int `json:"hours"`
+	Minutes int `json:"minutes"`
+	Seconds int `json:"seconds"`
+}
+
+type TaskResponse struct {
+	TaskID uuid.UUID `json:"taskID"`
+	Name   string    `json:"name"`
+	Duration
+	// Доля задачи в общих трудозатратах за период, в процентах
+	Percent float64 `json:"percent"`
+
+	duration time.Duration
+}
+
+type Report struct {
+	Total Duration       `json:"total"`
+	Tasks []TaskResponse `json:"tasks"`
+	Days  []Bucket       `json:"days,omitempty"`
+	Weeks []Bucket       `json:"weeks,omitempty"`
+
+	total time.Duration
+}
+
+type UserResponse struct {
+	UserID     uuid.UUID `json:"userID"`
+	Name       string    `json:"name"`
+	Surname    string    `json:"surname"`
+	Patronymic string    `json:"patronymic"`
+	*Report
+}
+
+type Team struct {
+	Total Duration       `json:"total"`
+	Users []UserResponse `json:"users"`
+}
+
+type TeamInput struct {
+	Input
+	Filters models.UserFilters `json:"filters"`
+}
+
+type ByDuration []TaskResponse
+
+func (a ByDuration) Len() int      { return len(a) }
+func (a ByDuration) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+
+// Less упорядочивает задачи по убыванию времени, а с равным временем - по названию и ID, чтобы порядок не менялся между запросами
+func (a ByDuration) Less(i, j int) bool {
+	if a[i].duration != a[j].duration {
+		return a[i].duration > a[j].duration
+	}
+	if a[i].Name != a[j].Name {
+		return a[i].Name < a[j].Name
+	}
+	return a[i].TaskID.String() < a[j].TaskID.String()
+}
+
+// Validate проверяет период и способ группировки
+func (input Input) Validate() error {
+	if !input.EndTime.After(input.StartTime) {
+		return fmt.Errorf("Конец периода должен быть позже начала")
+	}
+	switch input.GroupBy {
+	case "", GroupByDay, GroupByWeek:
+		return nil
+	default:
+		return fmt.Errorf("Некорректная группировка: %s. Допустимые значения: %s, %s", input.GroupBy, GroupByDay, GroupByWeek)
+	}
+}
+
+// UserReport считает трудозатраты пользователя за период
+func UserReport(tx *gorm.DB, userID uuid.UUID, input Input, now time.Time) (*Report, error) {
+	entries, err := periodEntries(tx, []uuid.UUID{userID}, input)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(tx.Statement.Context).Debugf("Получен список сессий пользователя %v: %v", userID, entries)
+
+	tasks, err := entriesTasks(tx, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildReport(entries, tasks, input, now), nil
+}
+
+// TeamReport считает трудозатраты сразу для нескольких пользователей.
+// Сессии и задачи загружаются одним запросом на всех
+func TeamReport(tx *gorm.DB, users []models.Users, input Input, now time.Time) (*Team, error) {
+	userIDs := []uuid.UUID{}
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+
+	entries, err := periodEntries(tx, userIDs, input)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(tx.Statement.Context).Debugf("Получено сессий пользователей за период: %d", len(entries))
+
+	tasks, err := entriesTasks(tx, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	userEntries := map[uuid.UUID][]models.TimeEntries{}
+	for _, entry := range entries {
+		userEntries[entry.UserID] = append(userEntries[entry.UserID], entry)
+	}
+
+	team := &Team{Users: []UserResponse{}}
+
+	var total time.Duration
+	for _, user := range users {
+		report := buildReport(userEntries[user.ID], tasks, input, now)
+		total += report.total
+
+		team.Users = append(team.Users, UserResponse{
+			UserID:     user.ID,
+			Name:       user.Name,
+			Surname:    user.Surname,
+			Patronymic: user.Patronymic,
+			Report:     report,
+		})
+	}
+	team.Total = newDuration(total)
+
+	return team, nil
+}
+
+// Получаем все сессии пользователей, пересекающиеся с периодом
+func periodEntries(tx *gorm.DB, userIDs []uuid.UUID, input Input) ([]models.TimeEntries, error) {
+	query := tx.Where("user_id IN (?) AND start_time < ?", userIDs, input.EndTime)
+	if input.IncludeRunning {
+		query = query.Where("(end_time > ? OR end_time IS NULL)", input.StartTime)
+	} else {
+		query = query.Where("end_time > ?", input.StartTime)
+	}
+
+	var entries []models.TimeEntries
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Загружаем задачи, по которым есть сессии
+func entriesTasks(tx *gorm.DB, entries []models.TimeEntries) (map[uuid.UUID]models.Tasks, error) {
+	taskIDs := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{}
+	for _, entry := range entries {
+		if !seen[entry.TaskID] {
+			seen[entry.TaskID] = true
+			taskIDs = append(taskIDs, entry.TaskID)
+		}
+	}
+
+	var tasks []models.Tasks
+	// Удалённые задачи тоже учитываем: отработанное по ним время никуда не девается
+	if err := tx.Unscoped().Where("ID IN (?)", taskIDs).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	result := map[uuid.UUID]models.Tasks{}
+	for _, task := range tasks {
+		result[task.ID] = task
+	}
+	return result, nil
+}
+
+func buildReport(entries []models.TimeEntries, tasks map[uuid.UUID]models.Tasks, input Input, now time.Time) *Report {
+	//Суммируем время всех сессий по каждой задаче, обрезая сессии по границам периода
+	var total time.Duration
+	durations := map[uuid.UUID]time.Duration{}
+	taskIDs := []uuid.UUID{}
+	for _, entry := range entries {
+		if _, ok := durations[entry.TaskID]; !ok {
+			taskIDs = append(taskIDs, entry.TaskID)
+		}
+		duration := timetracking.ClippedDuration(entry, input.StartTime, input.EndTime, now)
+		durations[entry.TaskID] += duration
+		total += duration
+	}
+
+	report := &Report{
+		Total: newDuration(total),
+		Tasks: []TaskResponse{},
+		total: total,
+	}
+
+	for _, taskID := range taskIDs {
+		report.Tasks = append(report.Tasks, TaskResponse{
+			TaskID:   taskID,
+			Name:     tasks[taskID].Name,
+			Duration: newDuration(durations[taskID]),
+			Percent:  percent(durations[taskID], total),
+			duration: durations[taskID],
+		})
+	}
+	//Сортируем от большей к меньшей
+	sort.Stable(ByDuration(report.Tasks))
+
+	switch input.GroupBy {
+	case GroupByDay:
+		report.Days = buckets(entries, input, now)
+	case GroupByWeek:
+		report.Weeks = buckets(entries, input, now)
+	}
+
+	return report
+}
+
+func newDuration(duration time.Duration) Duration {
+	var result Duration
+	result.Hours, result.Minutes, result.Seconds = timetracking.SplitDuration(duration)
+	return result
+}
+
+// Процент с точностью до сотых
+func percent(part, total time.Duration) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
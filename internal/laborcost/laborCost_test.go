@@ -0,0 +1,43 @@
+package laborcost
+
+import (
+	"github.com/google/uuid"
+	"test/internal/models"
+	"testing"
+	"time"
+)
+
+func TestBuildReportOrder(t *testing.T) {
+	start := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	input := Input{Period: Period{StartTime: start, EndTime: start.Add(24 * time.Hour)}}
+
+	long := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	first := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	second := uuid.MustParse("00000000-0000-0000-0000-000000000003")
+	named := uuid.MustParse("00000000-0000-0000-0000-000000000004")
+	tasks := map[uuid.UUID]models.Tasks{
+		long:   {ID: long, Name: "Я"},
+		second: {ID: second, Name: "Б"},
+		first:  {ID: first, Name: "Б"},
+		named:  {ID: named, Name: "А"},
+	}
+
+	entry := func(taskID uuid.UUID, hours int) models.TimeEntries {
+		end := start.Add(time.Duration(hours) * time.Hour)
+		return models.TimeEntries{TaskID: taskID, StartTime: start, EndTime: &end}
+	}
+	entries := []models.TimeEntries{entry(first, 1), entry(second, 1), entry(named, 1), entry(long, 2)}
+	want := []uuid.UUID{long, named, first, second}
+
+	// Порядок сессий не должен влиять на порядок задач с равным временем
+	for i := 0; i < len(entries); i++ {
+		rotated := append(append([]models.TimeEntries{}, entries[i:]...), entries[:i]...)
+		report := buildReport(rotated, tasks, input, start.Add(24*time.Hour))
+
+		for j, task := range report.Tasks {
+			if task.TaskID != want[j] {
+				t.Fatalf("сдвиг %d: задача %d - %v (%s), ожидалась %v", i, j, task.TaskID, task.Name, want[j])
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+)
+
+type contextKey struct{}
+
+var discard = &logrus.Logger{Out: io.Discard, Formatter: new(logrus.TextFormatter), Hooks: make(logrus.LevelHooks), Level: logrus.PanicLevel}
+
+// WithLogger кладет логгер в контекст, чтобы им пользовались функции, которым передается только контекст или транзакция
+func WithLogger(ctx context.Context, log logrus.FieldLogger) context.Context {
+	return context.WithValue(ctx, contextKey{}, log)
+}
+
+// FromContext возвращает логгер из контекста. Без него записи отбрасываются
+func FromContext(ctx context.Context) logrus.FieldLogger {
+	if ctx != nil {
+		if log, ok := ctx.Value(contextKey{}).(logrus.FieldLogger); ok {
+			return log
+		}
+	}
+	return discard
+}
+
+// Middleware кладет логгер в контекст каждого запроса
+func Middleware(log logrus.FieldLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(WithLogger(r.Context(), log)))
+		})
+	}
+}
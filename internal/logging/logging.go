@@ -2,17 +2,19 @@ package logging
 
 import "github.com/sirupsen/logrus"
 
-var Log *logrus.Logger
-
-func InitLogger() {
-	Log = logrus.New()
+func NewLogger() *logrus.Logger {
+	log := logrus.New()
 	// Настройка формата логирования
-	Log.SetFormatter(&logrus.JSONFormatter{
+	log.SetFormatter(&logrus.JSONFormatter{
 		TimestampFormat: "2006-01-02 15:04:05.000",
 		PrettyPrint:     true,
 	})
 	// Установка уровня логирования на DEBUG (включает INFO)
-	Log.SetLevel(logrus.DebugLevel)
+	log.SetLevel(logrus.DebugLevel)
+	// Персональные данные пользователей в полях логов маскируются
+	log.AddHook(redactHook{})
+
+	log.Info("Логгер инициализирован")
 
-	Log.Info("Логгер инициализирован")
+	return log
 }
@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"test/internal/pii"
+)
+
+// Поля с ФИО и адресом. Паспортные поля определяются по подстроке в названии
+var personalFields = map[string]bool{
+	"name":       true,
+	"surname":    true,
+	"patronymic": true,
+	"address":    true,
+}
+
+// redactHook маскирует персональные данные в полях записи перед выводом в лог
+type redactHook struct{}
+
+func (redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (redactHook) Fire(entry *logrus.Entry) error {
+	for key, value := range entry.Data {
+		if value == nil {
+			continue
+		}
+
+		lowerKey := strings.ToLower(key)
+		switch {
+		case strings.Contains(lowerKey, "passport") || strings.Contains(lowerKey, "pussport"):
+			entry.Data[key] = pii.MaskPassportValue(fmt.Sprint(value))
+		case strings.HasPrefix(lowerKey, "user_") && personalFields[strings.TrimPrefix(lowerKey, "user_")]:
+			entry.Data[key] = pii.MaskText(fmt.Sprint(value))
+		}
+	}
+	return nil
+}
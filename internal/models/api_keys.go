@@ -0,0 +1,51 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// APIKeyScope - действие, разрешенное ключу API
+type APIKeyScope string
+
+const (
+	ScopeReportsRead APIKeyScope = "reports:read"
+	ScopeTasksWrite  APIKeyScope = "tasks:write"
+)
+
+func (s APIKeyScope) Valid() bool {
+	switch s {
+	case ScopeReportsRead, ScopeTasksWrite:
+		return true
+	}
+	return false
+}
+
+// APIKeys - ключ для интеграций без входа пользователя. Сам ключ не хранится, только его хеш
+type APIKeys struct {
+	ID   uuid.UUID `gorm:"primaryKey;type:uuid" json:"id"`
+	Name string    `gorm:"not null" json:"name"`
+	// Начало ключа, чтобы его можно было узнать в списке
+	Prefix     string        `gorm:"not null" json:"prefix"`
+	KeyHash    string        `gorm:"not null;uniqueIndex" json:"-"`
+	Scopes     []APIKeyScope `gorm:"serializer:json;not null" json:"scopes"`
+	CreatedBy  *uuid.UUID    `gorm:"index" json:"createdBy,omitempty"`
+	LastUsedAt *time.Time    `json:"lastUsedAt"`
+	RevokedAt  *time.Time    `json:"revokedAt"`
+	CreatedAt  time.Time     `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt  time.Time     `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (k APIKeys) HasScope(scope APIKeyScope) bool {
+	for _, keyScope := range k.Scopes {
+		if keyScope == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type APIKeysCreateInput struct {
+	Name   string        `json:"name"`
+	Scopes []APIKeyScope `json:"scopes"`
+}
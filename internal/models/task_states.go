@@ -0,0 +1,44 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// TaskState - состояние жизненного цикла задачи
+type TaskState string
+
+const (
+	TaskStateNew        TaskState = "new"
+	TaskStateInProgress TaskState = "in_progress"
+	TaskStatePaused     TaskState = "paused"
+	TaskStateDone       TaskState = "done"
+	TaskStateCancelled  TaskState = "cancelled"
+)
+
+// Допустимые переходы между состояниями задачи
+var taskStateTransitions = map[TaskState][]TaskState{
+	TaskStateNew:        {TaskStateInProgress, TaskStateCancelled},
+	TaskStateInProgress: {TaskStatePaused, TaskStateDone, TaskStateCancelled},
+	TaskStatePaused:     {TaskStateInProgress, TaskStateDone, TaskStateCancelled},
+}
+
+func (s TaskState) CanTransitionTo(next TaskState) bool {
+	for _, state := range taskStateTransitions[s] {
+		if state == next {
+			return true
+		}
+	}
+	return false
+}
+
+// TaskStateTransitions - история смены состояний задачи
+type TaskStateTransitions struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid" json:"id"`
+	TaskID    uuid.UUID `gorm:"index;not null" json:"taskID"`
+	FromState TaskState `gorm:"type:varchar(16);not null" json:"fromState"`
+	ToState   TaskState `gorm:"type:varchar(16);not null" json:"toState"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index" json:"createdAt"`
+	// Пользователь, выполнивший переход. Пусто для переходов до появления аутентификации
+	ActorID *uuid.UUID `gorm:"index" json:"actorID,omitempty"`
+}
@@ -2,25 +2,59 @@ package models
 
 import (
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"test/internal/pagination"
 	"time"
 )
 
 type Tasks struct {
-	ID          uuid.UUID  `gorm:"primaryKey;type:uuid" json:"id"`
-	Name        string     `gorm:"not null" json:"name"`
-	Description string     `gorm:"not null" json:"description"`
-	Status      bool       `gorm:"default:false" json:"status"`
-	Hours       int        `gorm:"default:0;not null" json:"hours"`
-	Minutes     int        `gorm:"default:0;not null" json:"minutes"`
-	Seconds     int        `gorm:"default:0;not null" json:"seconds"`
-	StartTime   *time.Time `gorm:"index"`
-	EndTime     *time.Time `gorm:"index"`
-	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"createdAt"`
-	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
-	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+	ID          uuid.UUID      `gorm:"primaryKey;type:uuid" json:"id"`
+	Name        string         `gorm:"not null" json:"name"`
+	Description string         `gorm:"not null" json:"description"`
+	State       TaskState      `gorm:"type:varchar(16);default:new;not null;index" json:"state"`
+	Hours       int            `gorm:"default:0;not null" json:"hours"`
+	Minutes     int            `gorm:"default:0;not null" json:"minutes"`
+	Seconds     int            `gorm:"default:0;not null" json:"seconds"`
+	StartTime   *time.Time     `gorm:"index"`
+	EndTime     *time.Time     `gorm:"index"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt `json:"deletedAt"`
 }
 
 type TasksCreateInput struct {
 	UserID uuid.UUID `json:"user_id"`
 	Tasks  Tasks     `json:"task"`
 }
+
+type TasksUpdateInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type TaskFilter struct {
+	Field    string `json:"field"`
+	Value    string `json:"value"`
+	Operator string `json:"operator"`
+	// Значения для операторов in и between
+	Values          []string `json:"values"`
+	CaseInsensitive bool     `json:"caseInsensitive"`
+}
+
+type TaskFilters struct {
+	Filters []TaskFilter `json:"filters"`
+}
+
+type TaskGetListInput struct {
+	Filters TaskFilters `json:"filters"`
+	Page    int         `json:"page"`
+	Limit   int         `json:"limit"`
+	// Режим пагинации: offset (по умолчанию) или cursor
+	Pagination string `json:"pagination"`
+	Cursor     string `json:"cursor"`
+}
+
+type TaskListResponse struct {
+	Items []Tasks `json:"items"`
+	pagination.Envelope
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// TimeEntries - одна сессия работы над задачей (от старта до остановки таймера)
+type TimeEntries struct {
+	ID        uuid.UUID      `gorm:"primaryKey;type:uuid" json:"id"`
+	TaskID    uuid.UUID      `gorm:"index;not null" json:"taskID"`
+	UserID    uuid.UUID      `gorm:"index;not null" json:"userID"`
+	StartTime time.Time      `gorm:"index;not null" json:"startTime"`
+	EndTime   *time.Time     `gorm:"index" json:"endTime"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `json:"-"`
+}
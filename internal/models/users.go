@@ -1,36 +1,148 @@
 package models
 
 import (
+	"encoding/json"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"test/internal/pagination"
+	"test/internal/pii"
 	"time"
 )
 
 type Users struct {
-	ID             uuid.UUID  `gorm:"primaryKey;type:uuid"`
-	Name           string     `gorm:"not null" json:"name"`
-	Surname        string     `gorm:"not null" json:"surname"`
-	Patronymic     string     `gorm:"not null" json:"patronymic"`
-	Address        string     `gorm:"not null" json:"address"`
-	PassportSerie  string     `gorm:"not null" json:"passportSerie"`
-	PassportNumber string     `gorm:"not null" json:"passportNumber"`
-	FullPassport   string     `gorm:"unique"`
-	CreatedAt      time.Time  `gorm:"autoCreateTime"`
-	UpdatedAt      time.Time  `gorm:"autoUpdateTime"`
-	DeletedAt      *time.Time `json:"deletedAt,omitempty"`
+	ID             uuid.UUID      `gorm:"primaryKey;type:uuid"`
+	Name           string         `gorm:"not null" json:"name"`
+	Surname        string         `gorm:"not null" json:"surname"`
+	Patronymic     string         `gorm:"not null" json:"patronymic"`
+	Address        string         `gorm:"not null" json:"address"`
+	PassportSerie  string         `gorm:"not null;serializer:encrypted" json:"passportSerie"`
+	PassportNumber string         `gorm:"not null;serializer:encrypted" json:"passportNumber"`
+	FullPassport   string         `gorm:"serializer:encrypted"`
+	PassportHash   string         `gorm:"unique" json:"-"`
+	PasswordHash   string         `gorm:"not null;default:''" json:"-"`
+	Role           UserRole       `gorm:"type:varchar(16);not null;default:employee" json:"role"`
+	ManagerID      *uuid.UUID     `gorm:"index" json:"managerID,omitempty"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt      gorm.DeletedAt `json:"deletedAt"`
 }
 
+// UserRole - роль пользователя. Руководителю (manager) доступны задачи и трудозатраты
+// пользователей, у которых он указан в ManagerID
+type UserRole string
+
+const (
+	RoleAdmin    UserRole = "admin"
+	RoleManager  UserRole = "manager"
+	RoleEmployee UserRole = "employee"
+)
+
+func (r UserRole) Valid() bool {
+	switch r {
+	case RoleAdmin, RoleManager, RoleEmployee:
+		return true
+	}
+	return false
+}
+
+// Masked возвращает копию пользователя со скрытыми паспортными данными, например 45** ****56
+func (u Users) Masked() Users {
+	u.FullPassport = pii.MaskPassport(u.PassportSerie, u.PassportNumber)
+	u.PassportSerie = pii.MaskSerie(u.PassportSerie)
+	u.PassportNumber = pii.MaskNumber(u.PassportNumber)
+	return u
+}
+
+// UsersCreateInput - данные нового пользователя. id задает сервер, роль и руководителя назначает администратор через PATCH /users/{id}
+type UsersCreateInput struct {
+	Name           string `json:"name"`
+	Surname        string `json:"surname"`
+	Patronymic     string `json:"patronymic"`
+	Address        string `json:"address"`
+	PassportSerie  string `json:"passportSerie"`
+	PassportNumber string `json:"passportNumber"`
+}
+
+// UsersPasswordInput - новый пароль пользователя
+type UsersPasswordInput struct {
+	Password string `json:"password"`
+}
+
+// UsersPatchInput - частичное обновление пользователя, nil означает, что поле не меняется.
+// managerID: null снимает руководителя, поэтому отсутствие поля отличается от null через OptionalUUID
+type UsersPatchInput struct {
+	Name           *string      `json:"name"`
+	Surname        *string      `json:"surname"`
+	Patronymic     *string      `json:"patronymic"`
+	Address        *string      `json:"address"`
+	PassportSerie  *string      `json:"passportSerie"`
+	PassportNumber *string      `json:"passportNumber"`
+	Role           *UserRole    `json:"role"`
+	ManagerID      OptionalUUID `json:"managerID"`
+}
+
+// OptionalUUID - UUID, для которого важно, было ли поле в JSON: Set - поле передано, Value == nil - передан null
+type OptionalUUID struct {
+	Set   bool
+	Value *uuid.UUID
+}
+
+func (o *OptionalUUID) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Value = nil
+		return nil
+	}
+
+	var id uuid.UUID
+	if err := json.Unmarshal(data, &id); err != nil {
+		return err
+	}
+	o.Value = &id
+	return nil
+}
+
+// UsersSearchVector - выражение для полнотекстового поиска по пользователям.
+// По нему же построен GIN-индекс, поэтому в запросах оно должно совпадать дословно
+const UsersSearchVector = "to_tsvector('russian', coalesce(name, '') || ' ' || coalesce(surname, '') || ' ' || coalesce(patronymic, '') || ' ' || coalesce(address, ''))"
+
 type UserFilter struct {
 	Field    string `json:"field"`
 	Value    string `json:"value"`
 	Operator string `json:"operator"`
+	// Значения для операторов in и between
+	Values          []string `json:"values"`
+	CaseInsensitive bool     `json:"caseInsensitive"`
 }
 
+// UserFilters - условия из Filters объединяются через AND,
+// все группы из And должны выполняться, из Or - хотя бы одна
 type UserFilters struct {
-	Filters []UserFilter `json:"filters"`
+	Filters []UserFilter  `json:"filters"`
+	And     []UserFilters `json:"and"`
+	Or      []UserFilters `json:"or"`
+}
+
+type UserSort struct {
+	Field     string `json:"field"`
+	Direction string `json:"direction"`
 }
 
 type UserGetListInput struct {
+	// Полнотекстовый поиск по имени, фамилии, отчеству и адресу
+	Q       string      `json:"q"`
 	Filters UserFilters `json:"filters"`
+	Sort    []UserSort  `json:"sort"`
 	Page    int         `json:"page"`
 	Limit   int         `json:"limit"`
+	// Режим пагинации: offset (по умолчанию) или cursor
+	Pagination string `json:"pagination"`
+	Cursor     string `json:"cursor"`
+	// Включать в выборку удаленных пользователей
+	IncludeDeleted bool `json:"includeDeleted"`
+}
+
+type UserListResponse struct {
+	Items []Users `json:"items"`
+	pagination.Envelope
 }
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"testing"
+)
+
+func TestUsersPatchInputManagerID(t *testing.T) {
+	managerID := uuid.New()
+
+	tests := []struct {
+		name      string
+		body      string
+		wantSet   bool
+		wantValue *uuid.UUID
+		wantErr   bool
+	}{
+		{name: "поле не передано", body: `{"name":"Иван"}`},
+		{name: "явный null снимает руководителя", body: `{"managerID":null}`, wantSet: true},
+		{name: "новый руководитель", body: `{"managerID":"` + managerID.String() + `"}`, wantSet: true, wantValue: &managerID},
+		{name: "некорректный ID", body: `{"managerID":"abc"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var input UsersPatchInput
+			err := json.Unmarshal([]byte(tt.body), &input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ожидалась ошибка")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+
+			if input.ManagerID.Set != tt.wantSet {
+				t.Fatalf("Set = %v, ожидалось %v", input.ManagerID.Set, tt.wantSet)
+			}
+			if (input.ManagerID.Value == nil) != (tt.wantValue == nil) || (tt.wantValue != nil && *input.ManagerID.Value != *tt.wantValue) {
+				t.Fatalf("Value = %v, ожидалось %v", input.ManagerID.Value, tt.wantValue)
+			}
+		})
+	}
+}
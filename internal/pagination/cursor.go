@@ -0,0 +1,69 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	ModeOffset = "offset"
+	ModeCursor = "cursor"
+)
+
+// DefaultOrder - порядок записей по умолчанию в обоих режимах пагинации. Курсор задает позицию именно в нем
+const DefaultOrder = "created_at, id"
+
+// Cursor - позиция последней отданной записи. Клиенту отдается как непрозрачная строка
+type Cursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        uuid.UUID `json:"i"`
+}
+
+// IsCursorMode определяет режим пагинации. Переданный курсор сам по себе включает режим курсора
+func IsCursorMode(mode, token string) (bool, error) {
+	switch mode {
+	case "", ModeOffset:
+		return token != "", nil
+	case ModeCursor:
+		return true, nil
+	default:
+		return false, fmt.Errorf("Некорректный режим пагинации: %s. Допустимые значения: %s, %s", mode, ModeOffset, ModeCursor)
+	}
+}
+
+func Encode(createdAt time.Time, id uuid.UUID) string {
+	data, _ := json.Marshal(Cursor{CreatedAt: createdAt, ID: id})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func Decode(token string) (Cursor, error) {
+	var cursor Cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, fmt.Errorf("Некорректный курсор")
+	}
+	if err = json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil {
+		return cursor, fmt.Errorf("Некорректный курсор")
+	}
+
+	return cursor, nil
+}
+
+// Apply добавляет в запрос условие и порядок keyset-пагинации по (created_at, id).
+// Выбирается limit+1 запись, чтобы понять, есть ли следующая страница
+func Apply(query *gorm.DB, token string, limit int) (*gorm.DB, error) {
+	if token != "" {
+		cursor, err := Decode(token)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("(created_at, id) > (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	return query.Order(DefaultOrder).Limit(limit + 1), nil
+}
@@ -0,0 +1,93 @@
+package pagination
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	DefaultPage  = 1
+	DefaultLimit = 10
+	// MaxLimit - наибольший размер страницы, больший limit урезается до него
+	MaxLimit = 100
+)
+
+// Params - разобранные параметры пагинации списка
+type Params struct {
+	Cursor bool
+	Token  string
+	Page   int
+	Limit  int
+}
+
+// Parse проверяет режим пагинации, подставляет значения по умолчанию для page и limit и ограничивает limit сверху MaxLimit
+func Parse(mode, token string, page, limit int) (Params, error) {
+	cursorMode, err := IsCursorMode(mode, token)
+	if err != nil {
+		return Params{}, err
+	}
+
+	params := Params{Cursor: cursorMode, Token: token, Page: DefaultPage, Limit: DefaultLimit}
+	if page > 0 {
+		params.Page = page
+	}
+	if limit > 0 {
+		params.Limit = min(limit, MaxLimit)
+	}
+
+	return params, nil
+}
+
+func (p Params) Offset() int {
+	return (p.Page - 1) * p.Limit
+}
+
+// Window ограничивает запрос выбранной страницей. В режиме offset order задает сортировку и должен заканчиваться
+// DefaultOrder, чтобы порядок был однозначным; без order записи идут в DefaultOrder, как и в режиме курсора
+func (p Params) Window(query *gorm.DB, order func(*gorm.DB) (*gorm.DB, error)) (*gorm.DB, error) {
+	if p.Cursor {
+		return Apply(query, p.Token, p.Limit)
+	}
+
+	if order == nil {
+		query = query.Order(DefaultOrder)
+	} else {
+		var err error
+		if query, err = order(query); err != nil {
+			return nil, err
+		}
+	}
+
+	return query.Limit(p.Limit).Offset(p.Offset()), nil
+}
+
+// Envelope - общая часть ответа со списком. В режиме курсора total и page не заполняются, а для следующей страницы отдается nextCursor
+type Envelope struct {
+	Total      *int64 `json:"total,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	HasNext    bool   `json:"hasNext"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// Result собирает конверт ответа по выбранной странице. В режиме курсора запрос выбирает limit+1 запись (см. Apply):
+// лишняя запись отбрасывается и означает, что есть следующая страница. total учитывается только в режиме offset
+func Result[T any](p Params, items []T, total int64, key func(T) (time.Time, uuid.UUID)) ([]T, Envelope) {
+	envelope := Envelope{Limit: p.Limit}
+
+	if p.Cursor {
+		if len(items) > p.Limit {
+			items = items[:p.Limit]
+			envelope.HasNext = true
+			envelope.NextCursor = Encode(key(items[len(items)-1]))
+		}
+		return items, envelope
+	}
+
+	envelope.Total = &total
+	envelope.Page = p.Page
+	envelope.HasNext = int64(p.Offset()+len(items)) < total
+
+	return items, envelope
+}
@@ -0,0 +1,229 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 7, 1, 9, 30, 0, 123456000, time.UTC)
+	id := uuid.New()
+
+	cursor, err := Decode(Encode(createdAt, id))
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if !cursor.CreatedAt.Equal(createdAt) || cursor.ID != id {
+		t.Fatalf("курсор %+v, ожидалось %v/%v", cursor, createdAt, id)
+	}
+}
+
+func TestDecodeRejectsTamperedCursor(t *testing.T) {
+	valid := Encode(time.Now(), uuid.New())
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "не base64", token: "!!!"},
+		{name: "обрезанный", token: valid[:len(valid)/2]},
+		{name: "не JSON", token: base64.RawURLEncoding.EncodeToString([]byte("created_at > 0"))},
+		{name: "пустой объект", token: base64.RawURLEncoding.EncodeToString([]byte("{}"))},
+		{name: "нулевой id", token: base64.RawURLEncoding.EncodeToString([]byte(`{"c":"2024-07-01T00:00:00Z","i":"00000000-0000-0000-0000-000000000000"}`))},
+		{name: "SQL в id", token: base64.RawURLEncoding.EncodeToString([]byte(`{"c":"2024-07-01T00:00:00Z","i":"1' OR '1'='1"}`))},
+		{name: "SQL в дате", token: base64.RawURLEncoding.EncodeToString([]byte(`{"c":"now(); DROP TABLE users","i":"` + uuid.NewString() + `"}`))},
+		{name: "стандартный base64 с дополнением", token: base64.StdEncoding.EncodeToString([]byte(`{"c":"2024-07-01T00:00:00Z","i":"` + uuid.NewString() + `"}`))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(tt.token); err == nil {
+				t.Fatalf("курсор %q принят", tt.token)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		token      string
+		page       int
+		limit      int
+		want       Params
+		wantOffset int
+		wantErr    bool
+	}{
+		{name: "значения по умолчанию", want: Params{Page: 1, Limit: 10}},
+		{name: "отрицательные значения", page: -2, limit: -5, want: Params{Page: 1, Limit: 10}},
+		{name: "третья страница", mode: ModeOffset, page: 3, limit: 20, want: Params{Page: 3, Limit: 20}, wantOffset: 40},
+		{name: "явный режим курсора", mode: ModeCursor, limit: 5, want: Params{Cursor: true, Page: 1, Limit: 5}},
+		{name: "курсор включает режим", token: "abc", want: Params{Cursor: true, Token: "abc", Page: 1, Limit: 10}},
+		{name: "limit больше максимального", limit: 1000, want: Params{Page: 1, Limit: 100}},
+		{name: "максимальный limit", limit: 100, want: Params{Page: 1, Limit: 100}},
+		{name: "неизвестный режим", mode: "keyset", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := Parse(tt.mode, tt.token, tt.page, tt.limit)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ожидалась ошибка")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if params != tt.want {
+				t.Fatalf("параметры %+v, ожидались %+v", params, tt.want)
+			}
+			if params.Offset() != tt.wantOffset {
+				t.Fatalf("смещение %d, ожидалось %d", params.Offset(), tt.wantOffset)
+			}
+		})
+	}
+}
+
+type record struct {
+	createdAt time.Time
+	id        uuid.UUID
+}
+
+func records(n int) []record {
+	items := make([]record, n)
+	for i := range items {
+		items[i] = record{createdAt: time.Date(2024, 7, 1, 0, i, 0, 0, time.UTC), id: uuid.New()}
+	}
+	return items
+}
+
+func recordKey(r record) (time.Time, uuid.UUID) { return r.createdAt, r.id }
+
+func TestResult(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      Params
+		items       int
+		total       int64
+		wantItems   int
+		wantHasNext bool
+		wantTotal   bool
+	}{
+		{name: "курсор, есть лишняя запись", params: Params{Cursor: true, Page: 1, Limit: 3}, items: 4, wantItems: 3, wantHasNext: true},
+		{name: "курсор, последняя страница", params: Params{Cursor: true, Page: 1, Limit: 3}, items: 3, wantItems: 3},
+		{name: "курсор, пусто", params: Params{Cursor: true, Page: 1, Limit: 3}, wantItems: 0},
+		{name: "offset, есть еще", params: Params{Page: 1, Limit: 3}, items: 3, total: 7, wantItems: 3, wantHasNext: true, wantTotal: true},
+		{name: "offset, последняя страница", params: Params{Page: 3, Limit: 3}, items: 1, total: 7, wantItems: 1, wantTotal: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := records(tt.items)
+
+			page, envelope := Result(tt.params, items, tt.total, recordKey)
+
+			if len(page) != tt.wantItems {
+				t.Fatalf("записей %d, ожидалось %d", len(page), tt.wantItems)
+			}
+			if envelope.HasNext != tt.wantHasNext || envelope.Limit != tt.params.Limit {
+				t.Fatalf("неожиданный конверт: %+v", envelope)
+			}
+			if (envelope.Total != nil) != tt.wantTotal || (tt.wantTotal && *envelope.Total != tt.total) {
+				t.Fatalf("total %v, ожидалось %d", envelope.Total, tt.total)
+			}
+			if tt.wantTotal && envelope.Page != tt.params.Page {
+				t.Fatalf("страница %d, ожидалась %d", envelope.Page, tt.params.Page)
+			}
+
+			if !tt.wantHasNext || !tt.params.Cursor {
+				if envelope.NextCursor != "" {
+					t.Fatalf("лишний nextCursor: %q", envelope.NextCursor)
+				}
+				return
+			}
+			// Следующая страница начинается после последней отданной записи, а не после лишней
+			cursor, err := Decode(envelope.NextCursor)
+			if err != nil {
+				t.Fatalf("nextCursor не разбирается: %v", err)
+			}
+			if last := page[len(page)-1]; cursor.ID != last.id || !cursor.CreatedAt.Equal(last.createdAt) {
+				t.Fatalf("курсор %+v указывает не на последнюю запись страницы", cursor)
+			}
+			if strings.Contains(envelope.NextCursor, "=") {
+				t.Fatalf("курсор с дополнением: %q", envelope.NextCursor)
+			}
+		})
+	}
+}
+
+type row struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+}
+
+func TestWindowOrder(t *testing.T) {
+	database, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost port=1 user=test dbname=test"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("не удалось создать БД: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		params   Params
+		order    func(*gorm.DB) (*gorm.DB, error)
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		{name: "курсор", params: Params{Cursor: true, Page: 1, Limit: 3}, wantSQL: "ORDER BY created_at, id LIMIT $1", wantVars: []interface{}{4}},
+		{name: "offset в том же порядке", params: Params{Page: 2, Limit: 3}, wantSQL: "ORDER BY created_at, id LIMIT $1 OFFSET $2", wantVars: []interface{}{3, 3}},
+		{
+			name:   "offset с явной сортировкой",
+			params: Params{Page: 1, Limit: 3},
+			order: func(query *gorm.DB) (*gorm.DB, error) {
+				return query.Order("name DESC").Order(DefaultOrder), nil
+			},
+			wantSQL:  "ORDER BY name DESC,created_at, id LIMIT $1",
+			wantVars: []interface{}{3},
+		},
+		{
+			name:   "курсор без явной сортировки",
+			params: Params{Cursor: true, Page: 1, Limit: 3},
+			order: func(query *gorm.DB) (*gorm.DB, error) {
+				return query.Order("name DESC").Order(DefaultOrder), nil
+			},
+			wantSQL:  "ORDER BY created_at, id LIMIT $1",
+			wantVars: []interface{}{4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := tt.params.Window(database.Model(&row{}), tt.order)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+
+			statement := query.Find(&[]row{}).Statement
+			if sql := statement.SQL.String(); !strings.HasSuffix(sql, tt.wantSQL) {
+				t.Fatalf("запрос %q не заканчивается на %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(statement.Vars, tt.wantVars) {
+				t.Fatalf("параметры %v, ожидались %v", statement.Vars, tt.wantVars)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package peopleinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"test/internal/logging"
+	"time"
+)
+
+var (
+	ErrNotConfigured = errors.New("Сервис получения данных по паспорту не настроен")
+	ErrNotFound      = errors.New("Сервис не нашел данных по паспорту")
+	ErrUnavailable   = errors.New("Сервис получения данных по паспорту недоступен")
+)
+
+// Client - клиент внешнего сервиса, который по паспорту возвращает ФИО и адрес
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	// Количество повторов после первой неудачной попытки
+	Retries    int
+	RetryDelay time.Duration
+}
+
+// People - ответ сервиса на GET /info
+type People struct {
+	Surname    string `json:"surname"`
+	Name       string `json:"name"`
+	Patronymic string `json:"patronymic"`
+	Address    string `json:"address"`
+}
+
+// NewClientFromEnv создает клиент по настройкам из окружения:
+// PEOPLE_INFO_URL, PEOPLE_INFO_TIMEOUT (например 5s) и PEOPLE_INFO_RETRIES.
+// Если PEOPLE_INFO_URL не задан, возвращается nil: получение данных по паспорту отключено
+func NewClientFromEnv(log logrus.FieldLogger) (*Client, error) {
+	baseURL := os.Getenv("PEOPLE_INFO_URL")
+	if baseURL == "" {
+		log.Warn("PEOPLE_INFO_URL не задан, получение данных пользователя по паспорту отключено")
+		return nil, nil
+	}
+
+	timeout := 5 * time.Second
+	if value := os.Getenv("PEOPLE_INFO_TIMEOUT"); value != "" {
+		var err error
+		if timeout, err = time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("Некорректное значение PEOPLE_INFO_TIMEOUT: %w", err)
+		}
+	}
+
+	retries := 2
+	if value := os.Getenv("PEOPLE_INFO_RETRIES"); value != "" {
+		var err error
+		if retries, err = strconv.Atoi(value); err != nil || retries < 0 {
+			return nil, fmt.Errorf("Некорректное значение PEOPLE_INFO_RETRIES: %q", value)
+		}
+	}
+
+	client := &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: timeout},
+		Retries:    retries,
+		RetryDelay: 200 * time.Millisecond,
+	}
+
+	log.WithFields(logrus.Fields{
+		"url":     client.BaseURL,
+		"timeout": timeout,
+		"retries": retries,
+	}).Info("Настроен клиент сервиса получения данных по паспорту")
+
+	return client, nil
+}
+
+// Info запрашивает данные человека по серии и номеру паспорта.
+// Сетевые ошибки и ответы 5xx повторяются, 404 означает, что данных нет
+func (c *Client) Info(ctx context.Context, passportSerie, passportNumber string) (*People, error) {
+	if c == nil {
+		return nil, ErrNotConfigured
+	}
+
+	query := url.Values{}
+	query.Set("passportSerie", passportSerie)
+	query.Set("passportNumber", passportNumber)
+	requestURL := c.BaseURL + "/info?" + query.Encode()
+
+	var lastErr error
+	for attempt := 0; attempt <= c.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("%w: %v", ErrUnavailable, ctx.Err())
+			case <-time.After(c.RetryDelay * time.Duration(attempt)):
+			}
+		}
+
+		people, retry, err := c.info(ctx, requestURL)
+		if err == nil {
+			return people, nil
+		}
+		if !retry {
+			return nil, err
+		}
+
+		lastErr = err
+		logging.FromContext(ctx).Warnf("Попытка %d запроса данных по паспорту не удалась: %v", attempt+1, err)
+	}
+
+	return nil, fmt.Errorf("%w: %v", ErrUnavailable, lastErr)
+}
+
+// info выполняет одну попытку запроса и сообщает, имеет ли смысл повторить её
+func (c *Client) info(ctx context.Context, requestURL string) (*People, bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrUnavailable, withoutURL(err))
+	}
+
+	response, err := c.HTTPClient.Do(request)
+	if err != nil {
+		err = withoutURL(err)
+		// Запрос клиента к нам уже отменен - повторять нет смысла
+		if ctx.Err() != nil {
+			return nil, false, fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
+		return nil, true, err
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusOK:
+	case response.StatusCode == http.StatusNotFound:
+		return nil, false, ErrNotFound
+	case response.StatusCode >= 500:
+		return nil, true, fmt.Errorf("сервис ответил %s", response.Status)
+	default:
+		return nil, false, fmt.Errorf("%w: сервис ответил %s", ErrUnavailable, response.Status)
+	}
+
+	var people People
+	if err = json.NewDecoder(response.Body).Decode(&people); err != nil {
+		return nil, false, fmt.Errorf("%w: некорректный ответ сервиса: %v", ErrUnavailable, err)
+	}
+	return &people, false, nil
+}
+
+// withoutURL убирает из ошибки адрес запроса: в его параметрах серия и номер паспорта,
+// а ошибка попадает в логи и в ответ клиенту
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
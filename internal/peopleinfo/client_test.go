@@ -0,0 +1,108 @@
+package peopleinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stub поднимает локальный сервер, который отвечает статусами из statuses по очереди.
+// Последний статус повторяется для всех следующих запросов
+func stub(t *testing.T, statuses ...int) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(atomic.AddInt32(&calls, 1)) - 1
+		if call >= len(statuses) {
+			call = len(statuses) - 1
+		}
+
+		if r.URL.Path != "/info" || r.URL.Query().Get("passportSerie") != "1234" || r.URL.Query().Get("passportNumber") != "567890" {
+			t.Errorf("неожиданный запрос %s", r.URL)
+		}
+
+		w.WriteHeader(statuses[call])
+		if statuses[call] == http.StatusOK {
+			json.NewEncoder(w).Encode(People{Surname: "Иванов", Name: "Иван", Patronymic: "Иванович", Address: "г. Москва"})
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func newTestClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: time.Second},
+		Retries:    2,
+		RetryDelay: time.Millisecond,
+	}
+}
+
+func TestClientInfo(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int
+		wantErr   error
+		wantCalls int32
+	}{
+		{name: "успешный ответ", statuses: []int{http.StatusOK}, wantCalls: 1},
+		{name: "повтор после 5xx", statuses: []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK}, wantCalls: 3},
+		{name: "повторы исчерпаны", statuses: []int{http.StatusServiceUnavailable}, wantErr: ErrUnavailable, wantCalls: 3},
+		{name: "данных нет", statuses: []int{http.StatusNotFound}, wantErr: ErrNotFound, wantCalls: 1},
+		{name: "4xx не повторяется", statuses: []int{http.StatusBadRequest}, wantErr: ErrUnavailable, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := stub(t, tt.statuses...)
+
+			people, err := newTestClient(server.URL).Info(context.Background(), "1234", "567890")
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ошибка %v, ожидалась %v", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("неожиданная ошибка: %v", err)
+				}
+				if people.Surname != "Иванов" || people.Patronymic != "Иванович" {
+					t.Fatalf("неожиданный ответ: %+v", people)
+				}
+			}
+
+			if got := atomic.LoadInt32(calls); got != tt.wantCalls {
+				t.Fatalf("запросов %d, ожидалось %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClientInfoUnreachableHidesPassport(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	_, err := newTestClient(server.URL).Info(context.Background(), "1234", "567890")
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("ошибка %v, ожидалась %v", err, ErrUnavailable)
+	}
+	if strings.Contains(err.Error(), "567890") {
+		t.Fatalf("ошибка содержит номер паспорта: %v", err)
+	}
+}
+
+func TestClientInfoNotConfigured(t *testing.T) {
+	var client *Client
+	if _, err := client.Info(context.Background(), "1234", "567890"); !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("ошибка %v, ожидалась %v", err, ErrNotConfigured)
+	}
+}
@@ -0,0 +1,235 @@
+package pii
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Префикс зашифрованного значения. Значения без него считаются записанными до включения шифрования
+const encryptedPrefix = "enc:v1:"
+
+var ErrKeysNotLoaded = errors.New("Ключи шифрования персональных данных не загружены")
+
+func init() {
+	schema.RegisterSerializer("encrypted", EncryptedSerializer{})
+}
+
+// Cipher шифрует персональные данные и считает их слепой индекс. Ключи хранятся в значении,
+// а не в пакете, поэтому в одном процессе могут работать экземпляры сервиса с разными ключами
+type Cipher struct {
+	aead     cipher.AEAD
+	indexKey []byte
+	// Шифр на ключах до ротации. Им только расшифровываются значения, которые еще не перешифрованы
+	previous *Cipher
+}
+
+// NewCipher создает шифр из ключа шифрования и ключа слепого индекса, оба по 32 байта
+func NewCipher(encryptionKey, indexKey []byte) (*Cipher, error) {
+	if len(encryptionKey) != 32 || len(indexKey) != 32 {
+		return nil, errors.New("Ключи шифрования персональных данных должны быть длиной 32 байта")
+	}
+
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead, indexKey: indexKey}, nil
+}
+
+// WithPrevious возвращает шифр, который дополнительно расшифровывает значения, зашифрованные ключами previous
+func (c *Cipher) WithPrevious(previous *Cipher) *Cipher {
+	return &Cipher{aead: c.aead, indexKey: c.indexKey, previous: previous}
+}
+
+// Rotating сообщает, что заданы ключи до ротации и паспортные данные нужно перешифровать
+func (c *Cipher) Rotating() bool {
+	return c != nil && c.previous != nil
+}
+
+// LoadCipher читает ключи шифрования и слепого индекса из окружения:
+// PASSPORT_ENCRYPTION_KEY и PASSPORT_INDEX_KEY, оба - 32 байта в base64.
+// При ротации прежние ключи передаются в PASSPORT_ENCRYPTION_KEY_PREVIOUS и PASSPORT_INDEX_KEY_PREVIOUS
+func LoadCipher() (*Cipher, error) {
+	current, err := loadCipher("PASSPORT_ENCRYPTION_KEY", "PASSPORT_INDEX_KEY")
+	if err != nil {
+		return nil, err
+	}
+	if os.Getenv("PASSPORT_ENCRYPTION_KEY_PREVIOUS") == "" && os.Getenv("PASSPORT_INDEX_KEY_PREVIOUS") == "" {
+		return current, nil
+	}
+
+	previous, err := loadCipher("PASSPORT_ENCRYPTION_KEY_PREVIOUS", "PASSPORT_INDEX_KEY_PREVIOUS")
+	if err != nil {
+		return nil, err
+	}
+	return current.WithPrevious(previous), nil
+}
+
+func loadCipher(encryptionKeyName, indexKeyName string) (*Cipher, error) {
+	encryptionKey, err := readKey(encryptionKeyName)
+	if err != nil {
+		return nil, err
+	}
+	indexKey, err := readKey(indexKeyName)
+	if err != nil {
+		return nil, err
+	}
+	return NewCipher(encryptionKey, indexKey)
+}
+
+func readKey(name string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(os.Getenv(name))
+	if err != nil {
+		return nil, fmt.Errorf("Некорректный ключ %s: %v", name, err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("Ключ %s должен быть длиной 32 байта", name)
+	}
+	return key, nil
+}
+
+// Encrypt шифрует значение AES-GCM со случайным nonce
+func (c *Cipher) Encrypt(plain string) (string, error) {
+	if c == nil {
+		return "", ErrKeysNotLoaded
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt расшифровывает значение. Незашифрованное значение возвращается как есть.
+// Значение, которое не расшифровывается текущим ключом, пробуем расшифровать ключом до ротации
+func (c *Cipher) Decrypt(stored string) (string, error) {
+	if !strings.HasPrefix(stored, encryptedPrefix) {
+		return stored, nil
+	}
+	if c == nil {
+		return "", ErrKeysNotLoaded
+	}
+
+	plain, err := c.open(stored)
+	if err != nil && c.previous != nil {
+		return c.previous.Decrypt(stored)
+	}
+	return plain, err
+}
+
+func (c *Cipher) open(stored string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, encryptedPrefix))
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < c.aead.NonceSize() {
+		return "", errors.New("Зашифрованное значение повреждено")
+	}
+
+	plain, err := c.aead.Open(nil, sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// BlindIndex - детерминированный HMAC значения. По нему ищутся и проверяются на уникальность
+// зашифрованные значения, которые сами по себе сравнить нельзя
+func (c *Cipher) BlindIndex(plain string) string {
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(plain))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type contextKey struct{}
+
+// WithCipher кладет шифр в контекст. Через контекст запроса к БД его получает EncryptedSerializer
+func WithCipher(ctx context.Context, c *Cipher) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext возвращает шифр из контекста или nil
+func FromContext(ctx context.Context) *Cipher {
+	if ctx == nil {
+		return nil
+	}
+	c, _ := ctx.Value(contextKey{}).(*Cipher)
+	return c
+}
+
+// Attach добавляет шифр в контекст каждого запроса, выполняемого через db.
+// Сериализаторы gorm регистрируются на весь процесс, а callbacks - на соединение,
+// поэтому разные соединения шифруют данные своими ключами
+func (c *Cipher) Attach(db *gorm.DB) error {
+	withCipher := func(tx *gorm.DB) {
+		tx.Statement.Context = WithCipher(tx.Statement.Context, c)
+	}
+
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("*").Register("pii:cipher", withCipher),
+		callbacks.Query().Before("*").Register("pii:cipher", withCipher),
+		callbacks.Update().Before("*").Register("pii:cipher", withCipher),
+		callbacks.Delete().Before("*").Register("pii:cipher", withCipher),
+		callbacks.Row().Before("*").Register("pii:cipher", withCipher),
+		callbacks.Raw().Before("*").Register("pii:cipher", withCipher),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EncryptedSerializer шифрует строковое поле при записи в БД и расшифровывает при чтении шифром из контекста запроса.
+// Подключается тегом gorm:"serializer:encrypted"
+type EncryptedSerializer struct{}
+
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var stored string
+	switch v := dbValue.(type) {
+	case nil:
+	case []byte:
+		stored = string(v)
+	case string:
+		stored = v
+	default:
+		return fmt.Errorf("Неподдерживаемый тип зашифрованного значения: %T", dbValue)
+	}
+
+	plain, err := FromContext(ctx).Decrypt(stored)
+	if err != nil {
+		return err
+	}
+
+	field.ReflectValueOf(ctx, dst).SetString(plain)
+	return nil
+}
+
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plain, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("Шифровать можно только строки, получено: %T", fieldValue)
+	}
+	return FromContext(ctx).Encrypt(plain)
+}
@@ -0,0 +1,276 @@
+package pii
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testCipher(t *testing.T, keyByte byte) *Cipher {
+	t.Helper()
+
+	c, err := NewCipher(bytes.Repeat([]byte{keyByte}, 32), bytes.Repeat([]byte{keyByte + 1}, 32))
+	if err != nil {
+		t.Fatalf("не удалось создать шифр: %v", err)
+	}
+	return c
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	c := testCipher(t, 1)
+
+	for _, plain := range []string{"1234", "567890", "1234567890", "", "Иванов Иван"} {
+		t.Run(plain, func(t *testing.T) {
+			encrypted, err := c.Encrypt(plain)
+			if err != nil {
+				t.Fatalf("не удалось зашифровать: %v", err)
+			}
+			if !strings.HasPrefix(encrypted, encryptedPrefix) {
+				t.Fatalf("нет префикса шифрования: %q", encrypted)
+			}
+			if plain != "" && strings.Contains(encrypted, plain) {
+				t.Fatalf("значение видно в шифротексте: %q", encrypted)
+			}
+
+			decrypted, err := c.Decrypt(encrypted)
+			if err != nil {
+				t.Fatalf("не удалось расшифровать: %v", err)
+			}
+			if decrypted != plain {
+				t.Fatalf("расшифровано %q, ожидалось %q", decrypted, plain)
+			}
+
+			// Nonce случайный, поэтому одинаковые значения не совпадают в БД
+			again, _ := c.Encrypt(plain)
+			if again == encrypted {
+				t.Fatal("повторное шифрование дало тот же шифротекст")
+			}
+		})
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	c := testCipher(t, 1)
+	encrypted, err := c.Encrypt("567890")
+	if err != nil {
+		t.Fatalf("не удалось зашифровать: %v", err)
+	}
+	sealed, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(encrypted, encryptedPrefix))
+	sealed[len(sealed)-1] ^= 0xff
+
+	tests := []struct {
+		name    string
+		cipher  *Cipher
+		stored  string
+		want    string
+		wantErr error
+		anyErr  bool
+	}{
+		{name: "открытый текст до включения шифрования", cipher: c, stored: "567890", want: "567890"},
+		{name: "открытый текст без ключей", cipher: nil, stored: "567890", want: "567890"},
+		{name: "зашифрованное без ключей", cipher: nil, stored: encrypted, wantErr: ErrKeysNotLoaded},
+		{name: "чужой ключ", cipher: testCipher(t, 3), stored: encrypted, anyErr: true},
+		{name: "измененный шифротекст", cipher: c, stored: encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), anyErr: true},
+		{name: "не base64", cipher: c, stored: encryptedPrefix + "!!!", anyErr: true},
+		{name: "короче nonce", cipher: c, stored: encryptedPrefix + base64.StdEncoding.EncodeToString([]byte("abc")), anyErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cipher.Decrypt(tt.stored)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ошибка %v, ожидалась %v", err, tt.wantErr)
+				}
+			case tt.anyErr:
+				if err == nil {
+					t.Fatalf("значение расшифровано: %q", got)
+				}
+			default:
+				if err != nil || got != tt.want {
+					t.Fatalf("получено %q, %v, ожидалось %q", got, err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEncryptWithoutKeys(t *testing.T) {
+	var c *Cipher
+	if _, err := c.Encrypt("567890"); !errors.Is(err, ErrKeysNotLoaded) {
+		t.Fatalf("ошибка %v, ожидалась %v", err, ErrKeysNotLoaded)
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	c := testCipher(t, 1)
+
+	if c.BlindIndex("1234567890") != c.BlindIndex("1234567890") {
+		t.Fatal("индекс одного значения различается")
+	}
+	if c.BlindIndex("1234567890") == c.BlindIndex("1234567891") {
+		t.Fatal("индексы разных значений совпали")
+	}
+	if c.BlindIndex("1234567890") == testCipher(t, 3).BlindIndex("1234567890") {
+		t.Fatal("индекс не зависит от ключа")
+	}
+	if strings.Contains(c.BlindIndex("1234567890"), "1234567890") {
+		t.Fatal("значение видно в индексе")
+	}
+}
+
+func TestNewCipherRejectsShortKeys(t *testing.T) {
+	tests := []struct {
+		name          string
+		encryptionKey []byte
+		indexKey      []byte
+	}{
+		{name: "короткий ключ шифрования", encryptionKey: make([]byte, 16), indexKey: make([]byte, 32)},
+		{name: "короткий ключ индекса", encryptionKey: make([]byte, 32), indexKey: make([]byte, 31)},
+		{name: "нет ключей"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCipher(tt.encryptionKey, tt.indexKey); err == nil {
+				t.Fatal("шифр создан")
+			}
+		})
+	}
+}
+
+func TestLoadCipher(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+
+	tests := []struct {
+		name          string
+		encryptionKey string
+		indexKey      string
+		wantErr       bool
+	}{
+		{name: "оба ключа", encryptionKey: key, indexKey: key},
+		{name: "нет ключа шифрования", indexKey: key, wantErr: true},
+		{name: "нет ключа индекса", encryptionKey: key, wantErr: true},
+		{name: "не base64", encryptionKey: "not base64!", indexKey: key, wantErr: true},
+		{name: "неверная длина", encryptionKey: base64.StdEncoding.EncodeToString([]byte("short")), indexKey: key, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PASSPORT_ENCRYPTION_KEY", tt.encryptionKey)
+			t.Setenv("PASSPORT_INDEX_KEY", tt.indexKey)
+
+			c, err := LoadCipher()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ошибка %v, ожидалась ошибка: %v", err, tt.wantErr)
+			}
+			if err == nil && c == nil {
+				t.Fatal("шифр не создан")
+			}
+		})
+	}
+}
+
+func TestEncryptedSerializerUsesContextCipher(t *testing.T) {
+	c := testCipher(t, 1)
+	ctx := WithCipher(context.Background(), c)
+
+	if FromContext(ctx) != c {
+		t.Fatal("шифр не найден в контексте")
+	}
+	if FromContext(context.Background()) != nil {
+		t.Fatal("шифр найден в пустом контексте")
+	}
+
+	var serializer EncryptedSerializer
+	stored, err := serializer.Value(ctx, nil, reflect.Value{}, "567890")
+	if err != nil {
+		t.Fatalf("не удалось зашифровать: %v", err)
+	}
+	if plain, err := c.Decrypt(stored.(string)); err != nil || plain != "567890" {
+		t.Fatalf("расшифровано %q, %v", plain, err)
+	}
+
+	if _, err = serializer.Value(context.Background(), nil, reflect.Value{}, "567890"); !errors.Is(err, ErrKeysNotLoaded) {
+		t.Fatalf("без шифра в контексте ошибка %v, ожидалась %v", err, ErrKeysNotLoaded)
+	}
+	if _, err = serializer.Value(ctx, nil, reflect.Value{}, 567890); err == nil {
+		t.Fatal("зашифровано нестроковое значение")
+	}
+}
+
+func TestRotation(t *testing.T) {
+	previous, current := testCipher(t, 1), testCipher(t, 3)
+	rotating := current.WithPrevious(previous)
+
+	old, err := previous.Encrypt("567890")
+	if err != nil {
+		t.Fatalf("не удалось зашифровать: %v", err)
+	}
+	fresh, err := rotating.Encrypt("567890")
+	if err != nil {
+		t.Fatalf("не удалось зашифровать: %v", err)
+	}
+
+	if !rotating.Rotating() || current.Rotating() {
+		t.Fatal("признак ротации определен неверно")
+	}
+	if plain, err := rotating.Decrypt(old); err != nil || plain != "567890" {
+		t.Fatalf("значение на прежнем ключе не расшифровано: %q, %v", plain, err)
+	}
+	if _, err = current.Decrypt(old); err == nil {
+		t.Fatal("новый ключ без прежнего расшифровал старое значение")
+	}
+	// Перешифрованное значение читается уже без прежних ключей
+	if plain, err := current.Decrypt(fresh); err != nil || plain != "567890" {
+		t.Fatalf("новое значение не расшифровано новым ключом: %q, %v", plain, err)
+	}
+	if _, err = previous.Decrypt(fresh); err == nil {
+		t.Fatal("прежний ключ расшифровал новое значение")
+	}
+	if rotating.BlindIndex("1234567890") != current.BlindIndex("1234567890") {
+		t.Fatal("слепой индекс считается не новым ключом")
+	}
+	if rotating.BlindIndex("1234567890") == previous.BlindIndex("1234567890") {
+		t.Fatal("слепой индекс не изменился после ротации")
+	}
+}
+
+func TestLoadCipherWithPreviousKeys(t *testing.T) {
+	key := func(b byte) string { return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32)) }
+
+	tests := []struct {
+		name         string
+		previousEnc  string
+		previousIdx  string
+		wantRotating bool
+		wantErr      bool
+	}{
+		{name: "без ротации"},
+		{name: "оба прежних ключа", previousEnc: key(1), previousIdx: key(2), wantRotating: true},
+		{name: "только прежний ключ шифрования", previousEnc: key(1), wantErr: true},
+		{name: "только прежний ключ индекса", previousIdx: key(2), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PASSPORT_ENCRYPTION_KEY", key(3))
+			t.Setenv("PASSPORT_INDEX_KEY", key(4))
+			t.Setenv("PASSPORT_ENCRYPTION_KEY_PREVIOUS", tt.previousEnc)
+			t.Setenv("PASSPORT_INDEX_KEY_PREVIOUS", tt.previousIdx)
+
+			c, err := LoadCipher()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ошибка %v, ожидалась ошибка: %v", err, tt.wantErr)
+			}
+			if err == nil && c.Rotating() != tt.wantRotating {
+				t.Fatalf("ротация %v, ожидалась %v", c.Rotating(), tt.wantRotating)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package pii
+
+import "strings"
+
+// MaskSerie оставляет видимыми первые две цифры серии паспорта: 45**
+func MaskSerie(serie string) string {
+	return keep(serie, 2, 0)
+}
+
+// MaskNumber оставляет видимыми последние две цифры номера паспорта: ****56
+func MaskNumber(number string) string {
+	return keep(number, 0, 2)
+}
+
+// MaskPassport маскирует паспорт целиком: 45** ****56
+func MaskPassport(serie, number string) string {
+	return MaskSerie(serie) + " " + MaskNumber(number)
+}
+
+// MaskPassportValue маскирует любое паспортное значение, когда неизвестно, серия это, номер или паспорт целиком
+func MaskPassportValue(value string) string {
+	return keep(value, 2, 2)
+}
+
+// MaskText оставляет видимой только первую букву, например для ФИО и адреса в логах
+func MaskText(text string) string {
+	return keep(text, 1, 0)
+}
+
+func keep(value string, head, tail int) string {
+	runes := []rune(value)
+	if len(runes) <= head+tail {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:head]) + strings.Repeat("*", len(runes)-head-tail) + string(runes[len(runes)-tail:])
+}
@@ -0,0 +1,56 @@
+package pii
+
+import "testing"
+
+func TestMasks(t *testing.T) {
+	tests := []struct {
+		name string
+		mask func(string) string
+		in   string
+		want string
+	}{
+		{name: "серия", mask: MaskSerie, in: "4510", want: "45**"},
+		{name: "номер", mask: MaskNumber, in: "123456", want: "****56"},
+		{name: "паспорт целиком", mask: MaskPassportValue, in: "4510123456", want: "45******56"},
+		{name: "короткое значение скрывается полностью", mask: MaskPassportValue, in: "123", want: "***"},
+		{name: "пустая серия", mask: MaskSerie, in: "", want: ""},
+		{name: "кириллица по символам", mask: MaskText, in: "Иванов", want: "И*****"},
+		{name: "одна буква", mask: MaskText, in: "Я", want: "*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mask(tt.in); got != tt.want {
+				t.Fatalf("%q замаскировано как %q, ожидалось %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	if got := MaskPassport("4510", "123456"); got != "45** ****56" {
+		t.Fatalf("паспорт замаскирован как %q", got)
+	}
+}
+
+// Маска шифрованного значения строится по расшифрованному тексту и не раскрывает скрытые цифры
+func TestMaskAfterDecrypt(t *testing.T) {
+	c := testCipher(t, 1)
+
+	for _, tt := range []struct{ serie, number string }{{"4510", "123456"}, {"0001", "000002"}} {
+		serie, _ := c.Encrypt(tt.serie)
+		number, _ := c.Encrypt(tt.number)
+
+		plainSerie, err := c.Decrypt(serie)
+		if err != nil {
+			t.Fatalf("не удалось расшифровать серию: %v", err)
+		}
+		plainNumber, err := c.Decrypt(number)
+		if err != nil {
+			t.Fatalf("не удалось расшифровать номер: %v", err)
+		}
+
+		masked := MaskPassport(plainSerie, plainNumber)
+		if want := tt.serie[:2] + "** ****" + tt.number[4:]; masked != want {
+			t.Fatalf("маска %q, ожидалась %q", masked, want)
+		}
+	}
+}
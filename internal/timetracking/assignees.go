@@ -0,0 +1,145 @@
+package timetracking
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"test/internal/logging"
+	"test/internal/models"
+	"time"
+)
+
+var (
+	ErrNotAssigned      = errors.New("Пользователь не назначен на задачу")
+	ErrAlreadyAssigned  = errors.New("Пользователь уже назначен на задачу")
+	ErrAssigneeRequired = errors.New("На задачу назначено несколько пользователей, укажите user_id")
+	ErrNoAssignees      = errors.New("На задачу не назначен ни один пользователь")
+)
+
+// TaskAssignees возвращает ID всех пользователей, назначенных на задачу
+func TaskAssignees(tx *gorm.DB, taskID uuid.UUID) ([]uuid.UUID, error) {
+	var usersTasks []models.UsersTasks
+	if err := tx.Where("task_id = ?", taskID).Order("created_at").Find(&usersTasks).Error; err != nil {
+		return nil, err
+	}
+
+	userIDs := []uuid.UUID{}
+	for _, userTask := range usersTasks {
+		userIDs = append(userIDs, userTask.UserID)
+	}
+	return userIDs, nil
+}
+
+// ResolveTaskUser определяет, на кого записывать сессию.
+// Если пользователь не указан, он однозначно определяется только для задачи с одним исполнителем
+func ResolveTaskUser(tx *gorm.DB, taskID, requested uuid.UUID) (uuid.UUID, error) {
+	assignees, err := TaskAssignees(tx, taskID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if requested == uuid.Nil {
+		switch len(assignees) {
+		case 0:
+			return uuid.Nil, ErrNoAssignees
+		case 1:
+			return assignees[0], nil
+		default:
+			return uuid.Nil, ErrAssigneeRequired
+		}
+	}
+
+	for _, userID := range assignees {
+		if userID == requested {
+			return requested, nil
+		}
+	}
+	return uuid.Nil, ErrNotAssigned
+}
+
+// Assign назначает пользователя на задачу
+func Assign(tx *gorm.DB, taskID, userID uuid.UUID) error {
+	var count int64
+	if err := tx.Model(&models.UsersTasks{}).Where("task_id = ? AND user_id = ?", taskID, userID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrAlreadyAssigned
+	}
+
+	userTask := models.UsersTasks{
+		TaskID: taskID,
+		UserID: userID,
+	}
+
+	var err error
+	userTask.ID, err = uuid.NewUUID()
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Create(&userTask).Error; err != nil {
+		return err
+	}
+
+	logging.FromContext(tx.Statement.Context).Debugf("Пользователь %v назначен на задачу %v", userID, taskID)
+
+	return nil
+}
+
+// Unassign снимает пользователя с задачи. Если у него был запущен таймер, сессия закрывается
+// и возвращается, чтобы вызывающий код решил, что делать с задачей дальше
+func Unassign(tx *gorm.DB, taskID, userID uuid.UUID, now time.Time) (*models.TimeEntries, error) {
+	// Время удаления связи задаем сами, чтобы по нему можно было восстановить связи удаленного пользователя
+	result := tx.Model(&models.UsersTasks{}).Where("task_id = ? AND user_id = ?", taskID, userID).Update("deleted_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrNotAssigned
+	}
+
+	logging.FromContext(tx.Statement.Context).Debugf("Пользователь %v снят с задачи %v", userID, taskID)
+
+	entry, err := OpenEntry(tx, taskID)
+	if err != nil || entry == nil || entry.UserID != userID {
+		return nil, err
+	}
+
+	return StopEntry(tx, taskID, now)
+}
+
+// UnassignAll снимает пользователя со всех задач. Задачи, над которыми он в этот момент работал,
+// ставятся на паузу до следующего старта
+func UnassignAll(tx *gorm.DB, userID uuid.UUID, now time.Time) error {
+	var usersTasks []models.UsersTasks
+	if err := tx.Where("user_id = ?", userID).Find(&usersTasks).Error; err != nil {
+		return err
+	}
+
+	for _, userTask := range usersTasks {
+		entry, err := Unassign(tx, userTask.TaskID, userID, now)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			continue
+		}
+
+		var task models.Tasks
+		if err = tx.First(&task, "id = ?", userTask.TaskID).Error; err != nil {
+			return err
+		}
+		if err = Transition(tx, &task, models.TaskStatePaused); err != nil {
+			return err
+		}
+		if err = ApplyTrackedTime(tx, &task); err != nil {
+			return err
+		}
+		if err = tx.Save(&task).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
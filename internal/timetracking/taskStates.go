@@ -0,0 +1,67 @@
+package timetracking
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"test/internal/auth"
+	"test/internal/logging"
+	"test/internal/models"
+)
+
+// TransitionError - недопустимый переход задачи между состояниями
+type TransitionError struct {
+	From models.TaskState
+	To   models.TaskState
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("Недопустимый переход задачи из состояния %s в %s", e.From, e.To)
+}
+
+// Transition переводит задачу в новое состояние и записывает переход в историю.
+// Обновление условное, поэтому параллельный запрос, успевший сменить состояние, получит TransitionError
+func Transition(tx *gorm.DB, task *models.Tasks, to models.TaskState) error {
+	from := task.State
+	if !from.CanTransitionTo(to) {
+		return &TransitionError{From: from, To: to}
+	}
+
+	result := tx.Model(&models.Tasks{}).Where("id = ? AND state = ?", task.ID, from).Update("state", to)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var current models.Tasks
+		if err := tx.Select("state").First(&current, "id = ?", task.ID).Error; err != nil {
+			return err
+		}
+		return &TransitionError{From: current.State, To: to}
+	}
+
+	transition := models.TaskStateTransitions{
+		TaskID:    task.ID,
+		FromState: from,
+		ToState:   to,
+	}
+	// Контекст транзакции несет вызывающего, если обработчик передал в неё контекст запроса
+	if identity, ok := auth.FromContext(tx.Statement.Context); ok && identity.UserID != uuid.Nil {
+		transition.ActorID = &identity.UserID
+	}
+
+	var err error
+	transition.ID, err = uuid.NewUUID()
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Create(&transition).Error; err != nil {
+		return err
+	}
+
+	task.State = to
+
+	logging.FromContext(tx.Statement.Context).Debugf("Задача %v переведена из состояния %s в %s", task.ID, from, to)
+
+	return nil
+}
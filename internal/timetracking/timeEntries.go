@@ -0,0 +1,131 @@
+package timetracking
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"test/internal/logging"
+	"test/internal/models"
+	"time"
+)
+
+// OpenEntry возвращает незакрытую сессию задачи или nil, если таймер не запущен.
+// Таймер у задачи один, как и её состояние: пока его ведет один исполнитель, остальные не могут
+// вести время по этой задаче одновременно. Это сделано намеренно, параллельная работа оформляется отдельными задачами
+func OpenEntry(tx *gorm.DB, taskID uuid.UUID) (*models.TimeEntries, error) {
+	var entry models.TimeEntries
+	err := tx.Where("task_id = ? AND end_time IS NULL", taskID).Order("start_time DESC").First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// StartEntry открывает новую сессию по задаче для пользователя
+func StartEntry(tx *gorm.DB, taskID, userID uuid.UUID, startTime time.Time) (*models.TimeEntries, error) {
+	entry := models.TimeEntries{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartTime: startTime,
+	}
+
+	var err error
+	entry.ID, err = uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Create(&entry).Error; err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(tx.Statement.Context).Debugf("Открыта сессия %v по задаче %v для пользователя %v", entry.ID, taskID, userID)
+
+	return &entry, nil
+}
+
+// StopEntry закрывает открытую сессию задачи. Если таймер не запущен, возвращает nil
+func StopEntry(tx *gorm.DB, taskID uuid.UUID, endTime time.Time) (*models.TimeEntries, error) {
+	entry, err := OpenEntry(tx, taskID)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	entry.EndTime = &endTime
+	if err = tx.Save(entry).Error; err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(tx.Statement.Context).Debugf("Закрыта сессия %v по задаче %v", entry.ID, taskID)
+
+	return entry, nil
+}
+
+// EntryDuration возвращает длительность сессии. Для незакрытой сессии считается время до now
+func EntryDuration(entry models.TimeEntries, now time.Time) time.Duration {
+	if entry.EndTime == nil {
+		return now.Sub(entry.StartTime)
+	}
+	return entry.EndTime.Sub(entry.StartTime)
+}
+
+// SplitDuration раскладывает длительность на часы, минуты и секунды
+func SplitDuration(duration time.Duration) (hours, minutes, seconds int) {
+	totalSeconds := int(duration.Seconds())
+
+	hours = totalSeconds / 3600
+	totalSeconds %= 3600
+
+	minutes = totalSeconds / 60
+	seconds = totalSeconds % 60
+
+	return hours, minutes, seconds
+}
+
+// ApplyTrackedTime записывает в задачу суммарное время всех закрытых сессий
+func ApplyTrackedTime(tx *gorm.DB, task *models.Tasks) error {
+	var entries []models.TimeEntries
+	if err := tx.Where("task_id = ? AND end_time IS NOT NULL", task.ID).Find(&entries).Error; err != nil {
+		return err
+	}
+
+	var total time.Duration
+	for _, entry := range entries {
+		total += EntryDuration(entry, *entry.EndTime)
+	}
+
+	task.Hours, task.Minutes, task.Seconds = SplitDuration(total)
+
+	return nil
+}
+
+// ClippedInterval возвращает часть сессии, попадающую в интервал [from, to].
+// Незакрытая сессия считается идущей до now. ok=false, если пересечения нет
+func ClippedInterval(entry models.TimeEntries, from, to, now time.Time) (start, end time.Time, ok bool) {
+	start = entry.StartTime
+	end = now
+	if entry.EndTime != nil {
+		end = *entry.EndTime
+	}
+
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+
+	return start, end, end.After(start)
+}
+
+// ClippedDuration возвращает длительность части сессии, попадающей в интервал [from, to]
+func ClippedDuration(entry models.TimeEntries, from, to, now time.Time) time.Duration {
+	start, end, ok := ClippedInterval(entry, from, to, now)
+	if !ok {
+		return 0
+	}
+	return end.Sub(start)
+}
@@ -0,0 +1,60 @@
+package timetracking
+
+import (
+	"test/internal/models"
+	"testing"
+	"time"
+)
+
+func at(hour, minute int) time.Time {
+	return time.Date(2024, 7, 1, hour, minute, 0, 0, time.UTC)
+}
+
+func closed(start, end time.Time) models.TimeEntries {
+	return models.TimeEntries{StartTime: start, EndTime: &end}
+}
+
+func TestClippedInterval(t *testing.T) {
+	from, to, now := at(9, 0), at(18, 0), at(12, 0)
+
+	tests := []struct {
+		name      string
+		entry     models.TimeEntries
+		now       time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+		wantOK    bool
+	}{
+		{name: "внутри периода", entry: closed(at(10, 0), at(11, 30)), now: now, wantStart: at(10, 0), wantEnd: at(11, 30), wantOK: true},
+		{name: "начата до периода", entry: closed(at(8, 0), at(10, 0)), now: now, wantStart: at(9, 0), wantEnd: at(10, 0), wantOK: true},
+		{name: "закончена после периода", entry: closed(at(17, 0), at(20, 0)), now: at(21, 0), wantStart: at(17, 0), wantEnd: at(18, 0), wantOK: true},
+		{name: "накрывает период", entry: closed(at(7, 0), at(20, 0)), now: at(21, 0), wantStart: at(9, 0), wantEnd: at(18, 0), wantOK: true},
+		{name: "до периода", entry: closed(at(7, 0), at(8, 0)), now: now, wantOK: false},
+		{name: "после периода", entry: closed(at(19, 0), at(20, 0)), now: at(21, 0), wantOK: false},
+		{name: "заканчивается на начале периода", entry: closed(at(8, 0), at(9, 0)), now: now, wantOK: false},
+		{name: "запущенный таймер идет до now", entry: models.TimeEntries{StartTime: at(11, 0)}, now: now, wantStart: at(11, 0), wantEnd: at(12, 0), wantOK: true},
+		{name: "запущенный таймер обрезается концом периода", entry: models.TimeEntries{StartTime: at(17, 0)}, now: at(23, 0), wantStart: at(17, 0), wantEnd: at(18, 0), wantOK: true},
+		{name: "запущенный таймер, начатый после now", entry: models.TimeEntries{StartTime: at(13, 0)}, now: now, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, ok := ClippedInterval(tt.entry, from, to, tt.now)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, ожидалось %v", ok, tt.wantOK)
+			}
+
+			wantDuration := time.Duration(0)
+			if tt.wantOK {
+				if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+					t.Fatalf("интервал [%v, %v], ожидался [%v, %v]", start, end, tt.wantStart, tt.wantEnd)
+				}
+				wantDuration = tt.wantEnd.Sub(tt.wantStart)
+			}
+
+			if got := ClippedDuration(tt.entry, from, to, tt.now); got != wantDuration {
+				t.Fatalf("длительность %v, ожидалась %v", got, wantDuration)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"test/internal/models"
+	"unicode/utf8"
+)
+
+func (v *Validator) ValidateCreateAPIKey(input *models.APIKeysCreateInput) error {
+	v.Log.Info("Начало валидации данных на создание ключа API")
+
+	v.Log.Debugf("В валидацию пришли следующие данные: name=%s, scopes=%v", input.Name, input.Scopes)
+
+	input.Name = strings.TrimSpace(input.Name)
+
+	if utf8.RuneCountInString(input.Name) == 0 {
+		v.Log.Error("Валидация названия ключа провалилась")
+		return fmt.Errorf("У ключа отсутствует название!")
+	}
+	if utf8.RuneCountInString(input.Name) > 255 {
+		v.Log.Error("Валидация названия ключа провалилась")
+		return fmt.Errorf("Название ключа должно быть не длиннее 255 символов!")
+	}
+
+	if len(input.Scopes) == 0 {
+		v.Log.Error("Валидация областей ключа провалилась")
+		return fmt.Errorf("У ключа должна быть хотя бы одна область доступа!")
+	}
+	seen := make(map[models.APIKeyScope]bool, len(input.Scopes))
+	scopes := input.Scopes[:0]
+	for _, scope := range input.Scopes {
+		if !scope.Valid() {
+			v.Log.Error("Валидация областей ключа провалилась")
+			return fmt.Errorf("Неизвестная область доступа %q!", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	input.Scopes = scopes
+
+	v.Log.Info("Валидация ключа API успешно завершена!")
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"test/internal/models"
+	"unicode/utf8"
+)
+
+func (v *Validator) ValidateCreateTask(task *models.Tasks) error {
+	v.Log.Info("Начало валидации данных на создание задачи")
+
+	v.Log.Debugf("В валидацию пришли следующие данные: name=%s, description=%s", task.Name, task.Description)
+
+	task.Name = strings.TrimSpace(task.Name)
+	task.Description = strings.TrimSpace(task.Description)
+
+	if err := v.validateTaskName(task.Name); err != nil {
+		return err
+	}
+
+	v.Log.Info("Валидация задачи успешно завершена!")
+
+	return nil
+}
+
+func (v *Validator) ValidateUpdateTask(task *models.TasksUpdateInput) error {
+	v.Log.Info("Начало валидации данных на обновление задачи")
+
+	v.Log.Debugf("В валидацию пришли следующие данные: name=%s, description=%s", task.Name, task.Description)
+
+	task.Name = strings.TrimSpace(task.Name)
+	task.Description = strings.TrimSpace(task.Description)
+
+	if err := v.validateTaskName(task.Name); err != nil {
+		return err
+	}
+
+	v.Log.Info("Валидация задачи успешно завершена!")
+
+	return nil
+}
+
+func (v *Validator) validateTaskName(name string) error {
+	if utf8.RuneCountInString(name) == 0 {
+		v.Log.Error("Валидация названия задачи провалилась")
+		return fmt.Errorf("У задачи отсутствует название!")
+	}
+	if utf8.RuneCountInString(name) > 255 {
+		v.Log.Error("Валидация названия задачи провалилась")
+		return fmt.Errorf("Название задачи должно быть не длиннее 255 символов!")
+	}
+
+	return nil
+}
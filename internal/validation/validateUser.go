@@ -1,20 +1,32 @@
 package validation
 
 import (
+	"errors"
 	"fmt"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 	"strings"
-	"test/internal/db"
-	"test/internal/logging"
 	"test/internal/models"
+	"test/internal/pii"
 	"unicode"
 	"unicode/utf8"
 )
 
-func ValidateCreateUser(user *models.Users) error {
-	logging.Log.Info("Начало валидации данных на создание пользователя")
+// Validator проверяет входные данные. DB нужна для проверок уникальности и существования связанных записей
+type Validator struct {
+	DB  *gorm.DB
+	Log logrus.FieldLogger
+	// Нужен для слепого индекса паспорта
+	Cipher *pii.Cipher
+}
+
+// ErrPassportTaken - паспорт уже принадлежит другому пользователю
+var ErrPassportTaken = errors.New("Пользователь с таким паспортом уже существует")
 
-	logging.Log.WithFields(logrus.Fields{
+func (v *Validator) ValidateCreateUser(user *models.Users) error {
+	v.Log.Info("Начало валидации данных на создание пользователя")
+
+	v.Log.WithFields(logrus.Fields{
 		"user_id":             user.ID,
 		"user_name":           user.Name,
 		"user_surname":        user.Surname,
@@ -27,44 +39,52 @@ func ValidateCreateUser(user *models.Users) error {
 		"user_updatedAt":      user.UpdatedAt,
 	}).Debug("В валидацию пришли следующие данные")
 
-	if err := validateUserName(user); err != nil {
-		logging.Log.Error("Валидация имени пользователя провалилась")
+	if err := v.validateUserName(user); err != nil {
+		v.Log.Error("Валидация имени пользователя провалилась")
 		return err
 	}
-	if err := validateUserSurname(user); err != nil {
-		logging.Log.Error("Валидация фамилии пользователя провалилась")
+	if err := v.validateUserSurname(user); err != nil {
+		v.Log.Error("Валидация фамилии пользователя провалилась")
 		return err
 	}
-	if err := validateUserPatronymic(user); err != nil {
-		logging.Log.Error("Валидация отчества пользователя провалилась")
+	if err := v.validateUserPatronymic(user); err != nil {
+		v.Log.Error("Валидация отчества пользователя провалилась")
 		return err
 	}
 	if err := validateAddress(user); err != nil {
-		logging.Log.Error("Валидация адреса пользователя провалилась")
+		v.Log.Error("Валидация адреса пользователя провалилась")
 		return err
 	}
-	if err := validatePassportSerie(user); err != nil {
-		logging.Log.Error("Валидация серии паспорта пользователя провалилась")
+	if err := v.validatePassportSerie(user); err != nil {
+		v.Log.Error("Валидация серии паспорта пользователя провалилась")
 		return err
 	}
-	if err := validatePassportNumber(user); err != nil {
-		logging.Log.Error("Валидация номера паспорта пользователя провалилась")
+	if err := v.validatePassportNumber(user); err != nil {
+		v.Log.Error("Валидация номера паспорта пользователя провалилась")
 		return err
 	}
-	if err := validateFullPassport(user); err != nil {
-		logging.Log.Error("Валидация полного номера паспорта пользователя провалилась")
+	if user.Role == "" {
+		user.Role = models.RoleEmployee
+	}
+	if err := validateRole(user); err != nil {
+		v.Log.Error("Валидация роли пользователя провалилась")
 		return err
 	}
+	if err := v.validateManager(user); err != nil {
+		v.Log.Error("Валидация руководителя пользователя провалилась")
+		return err
+	}
+	// Уникальность паспорта при создании проверяет уникальный индекс БД, так нет гонки между проверкой и вставкой
 
-	logging.Log.Info("Валидация пользователя успешно завершена!")
+	v.Log.Info("Валидация пользователя успешно завершена!")
 
 	return nil
 }
 
-func ValidateUpdateUser(user *models.Users) error {
-	logging.Log.Info("Начало валидации данных на обновление пользователя")
+func (v *Validator) ValidateUpdateUser(user *models.Users) error {
+	v.Log.Info("Начало валидации данных на обновление пользователя")
 
-	logging.Log.WithFields(logrus.Fields{
+	v.Log.WithFields(logrus.Fields{
 		"user_id":             user.ID,
 		"user_name":           user.Name,
 		"user_surname":        user.Surname,
@@ -77,78 +97,182 @@ func ValidateUpdateUser(user *models.Users) error {
 		"user_updatedAt":      user.UpdatedAt,
 	}).Debug("В валидацию пришли следующие данные")
 
-	if err := validateUserName(user); err != nil {
-		logging.Log.Error("Валидация имени пользователя провалилась")
+	if err := v.validateUserName(user); err != nil {
+		v.Log.Error("Валидация имени пользователя провалилась")
 		return err
 	}
-	if err := validateUserSurname(user); err != nil {
-		logging.Log.Error("Валидация фамилии пользователя провалилась")
+	if err := v.validateUserSurname(user); err != nil {
+		v.Log.Error("Валидация фамилии пользователя провалилась")
 		return err
 	}
-	if err := validateUserPatronymic(user); err != nil {
-		logging.Log.Error("Валидация отчества пользователя провалилась")
+	if err := v.validateUserPatronymic(user); err != nil {
+		v.Log.Error("Валидация отчества пользователя провалилась")
 		return err
 	}
 	if err := validateAddress(user); err != nil {
-		logging.Log.Error("Валидация адреса пользователя провалилась")
+		v.Log.Error("Валидация адреса пользователя провалилась")
 		return err
 	}
-	if err := validatePassportSerie(user); err != nil {
-		logging.Log.Error("Валидация серии паспорта пользователя провалилась")
+	if err := v.validatePassportSerie(user); err != nil {
+		v.Log.Error("Валидация серии паспорта пользователя провалилась")
 		return err
 	}
-	if err := validatePassportNumber(user); err != nil {
-		logging.Log.Error("Валидация номера паспорта пользователя провалилась")
+	if err := v.validatePassportNumber(user); err != nil {
+		v.Log.Error("Валидация номера паспорта пользователя провалилась")
+		return err
+	}
+	if err := v.validateFullPassport(user); err != nil {
+		v.Log.Error("Валидация полного номера паспорта пользователя провалилась")
+		return err
+	}
+	// Пустая роль при полном обновлении означает, что роль не меняется
+	if user.Role != "" {
+		if err := validateRole(user); err != nil {
+			v.Log.Error("Валидация роли пользователя провалилась")
+			return err
+		}
+	}
+	if err := v.validateManager(user); err != nil {
+		v.Log.Error("Валидация руководителя пользователя провалилась")
 		return err
 	}
 
-	logging.Log.Info("Валидация пользователя успешно завершена!")
+	v.Log.Info("Валидация пользователя успешно завершена!")
 
 	return nil
 }
 
-func validateUserName(user *models.Users) error {
-	logging.Log.Debugf("Длина имени=%d", utf8.RuneCountInString(user.Name))
+// ValidatePatchUser применяет к пользователю только переданные поля и проверяет их.
+// Возвращает колонки, которые нужно обновить
+func (v *Validator) ValidatePatchUser(user *models.Users, input *models.UsersPatchInput) ([]string, error) {
+	v.Log.Info("Начало валидации данных на частичное обновление пользователя")
+
+	columns := []string{}
+
+	if input.Name != nil {
+		user.Name = *input.Name
+		if err := v.validateUserName(user); err != nil {
+			v.Log.Error("Валидация имени пользователя провалилась")
+			return nil, err
+		}
+		columns = append(columns, "name")
+	}
+	if input.Surname != nil {
+		user.Surname = *input.Surname
+		if err := v.validateUserSurname(user); err != nil {
+			v.Log.Error("Валидация фамилии пользователя провалилась")
+			return nil, err
+		}
+		columns = append(columns, "surname")
+	}
+	if input.Patronymic != nil {
+		user.Patronymic = *input.Patronymic
+		if err := v.validateUserPatronymic(user); err != nil {
+			v.Log.Error("Валидация отчества пользователя провалилась")
+			return nil, err
+		}
+		columns = append(columns, "patronymic")
+	}
+	if input.Address != nil {
+		user.Address = *input.Address
+		if err := validateAddress(user); err != nil {
+			v.Log.Error("Валидация адреса пользователя провалилась")
+			return nil, err
+		}
+		columns = append(columns, "address")
+	}
+	if input.PassportSerie != nil {
+		user.PassportSerie = *input.PassportSerie
+		if err := v.validatePassportSerie(user); err != nil {
+			v.Log.Error("Валидация серии паспорта пользователя провалилась")
+			return nil, err
+		}
+		columns = append(columns, "passport_serie")
+	}
+	if input.PassportNumber != nil {
+		user.PassportNumber = *input.PassportNumber
+		if err := v.validatePassportNumber(user); err != nil {
+			v.Log.Error("Валидация номера паспорта пользователя провалилась")
+			return nil, err
+		}
+		columns = append(columns, "passport_number")
+	}
+
+	if input.Role != nil {
+		user.Role = *input.Role
+		if err := validateRole(user); err != nil {
+			v.Log.Error("Валидация роли пользователя провалилась")
+			return nil, err
+		}
+		columns = append(columns, "role")
+	}
+	if input.ManagerID.Set {
+		user.ManagerID = input.ManagerID.Value
+		if err := v.validateManager(user); err != nil {
+			v.Log.Error("Валидация руководителя пользователя провалилась")
+			return nil, err
+		}
+		columns = append(columns, "manager_id")
+	}
+
+	// Полный номер паспорта пересчитываем, только если изменилась серия или номер
+	if fullPassport := user.PassportSerie + user.PassportNumber; fullPassport != user.FullPassport {
+		user.FullPassport = fullPassport
+		user.PassportHash = v.Cipher.BlindIndex(fullPassport)
+		if err := v.validateFullPassport(user); err != nil {
+			v.Log.Error("Валидация полного номера паспорта пользователя провалилась")
+			return nil, err
+		}
+		columns = append(columns, "full_passport", "passport_hash")
+	}
+
+	v.Log.Info("Валидация пользователя успешно завершена!")
+
+	return columns, nil
+}
+
+func (v *Validator) validateUserName(user *models.Users) error {
+	v.Log.Debugf("Длина имени=%d", utf8.RuneCountInString(user.Name))
 
 	if utf8.RuneCountInString(user.Name) == 0 {
 		return fmt.Errorf("У пользователя отсутствует имя!")
 	}
 	if utf8.RuneCountInString(user.Name) < 2 {
-		return fmt.Errorf("Длинна имени пользователя должна быть не меньше 2х символов! Имя=%s", user.Name)
+		return fmt.Errorf("Длинна имени пользователя должна быть не меньше 2х символов!")
 	}
 	if !onlyLetters(user.Name) {
-		return fmt.Errorf("Имя пользователя должно содержать только буквы! Имя=%s", user.Name)
+		return fmt.Errorf("Имя пользователя должно содержать только буквы!")
 	}
 
 	user.Name = normalizedString(user.Name)
 
 	return nil
 }
-func validateUserSurname(user *models.Users) error {
-	logging.Log.Debugf("Длина фамилии=%d", utf8.RuneCountInString(user.Surname))
+func (v *Validator) validateUserSurname(user *models.Users) error {
+	v.Log.Debugf("Длина фамилии=%d", utf8.RuneCountInString(user.Surname))
 
 	if utf8.RuneCountInString(user.Surname) == 0 {
 		return fmt.Errorf("У пользователя отсутствует фамилия!")
 	}
 	if utf8.RuneCountInString(user.Surname) < 2 {
-		return fmt.Errorf("Длина фамилии пользователя должна быть не меньше 2-х символов! Фамилия=%s", user.Surname)
+		return fmt.Errorf("Длина фамилии пользователя должна быть не меньше 2-х символов!")
 	}
 	if !onlyLetters(user.Surname) {
-		return fmt.Errorf("Фамилия пользователя должна содержать только буквы! Фамилия=%s", user.Surname)
+		return fmt.Errorf("Фамилия пользователя должна содержать только буквы!")
 	}
 
 	user.Surname = normalizedString(user.Surname)
 
 	return nil
 }
-func validateUserPatronymic(user *models.Users) error {
-	logging.Log.Debugf("Длина отчества=%d", utf8.RuneCountInString(user.Patronymic))
+func (v *Validator) validateUserPatronymic(user *models.Users) error {
+	v.Log.Debugf("Длина отчества=%d", utf8.RuneCountInString(user.Patronymic))
 
 	if utf8.RuneCountInString(user.Patronymic) > 21 {
-		return fmt.Errorf("Слишком длинное отчество пользователя! Такого не существует! Отчество=%s", user.Patronymic)
+		return fmt.Errorf("Слишком длинное отчество пользователя! Такого не существует!")
 	}
 	if !onlyLetters(user.Patronymic) {
-		return fmt.Errorf("Отчество пользователя должно содержать только буквы! Отчество=%s", user.Patronymic)
+		return fmt.Errorf("Отчество пользователя должно содержать только буквы!")
 	}
 
 	user.Patronymic = normalizedString(user.Patronymic)
@@ -159,44 +283,74 @@ func validateAddress(user *models.Users) error {
 	for _, r := range user.Address {
 		// Если символ не является буквой, цифрой или одним из разрешённых специальных символов, возвращаем false
 		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(" .,-/", r) {
-			return fmt.Errorf("Адрес содержит запрещенные символы! Адрес=%s", user.Address)
+			return fmt.Errorf("Адрес содержит запрещенные символы!")
 		}
 	}
 	return nil
 }
-func validatePassportSerie(user *models.Users) error {
-	logging.Log.Debugf("Длина серии паспорта=%d", utf8.RuneCountInString(user.PassportSerie))
+func (v *Validator) validatePassportSerie(user *models.Users) error {
+	v.Log.Debugf("Длина серии паспорта=%d", utf8.RuneCountInString(user.PassportSerie))
 
 	if utf8.RuneCountInString(user.PassportSerie) != 4 {
-		return fmt.Errorf("Длина серии паспорта должна ровняться 4! Серия паспорта=%s", user.PassportSerie)
+		return fmt.Errorf("Длина серии паспорта должна ровняться 4!")
 	}
 	if !onlyNumbers(user.PassportSerie) {
-		return fmt.Errorf("Серия паспорта должна содержать только цифры! Серия паспорта=%s", user.PassportSerie)
+		return fmt.Errorf("Серия паспорта должна содержать только цифры!")
 	}
 	return nil
 }
-func validatePassportNumber(user *models.Users) error {
-	logging.Log.Debugf("Длина номера паспорта=%d", utf8.RuneCountInString(user.PassportNumber))
+func (v *Validator) validatePassportNumber(user *models.Users) error {
+	v.Log.Debugf("Длина номера паспорта=%d", utf8.RuneCountInString(user.PassportNumber))
 
 	if utf8.RuneCountInString(user.PassportNumber) != 6 {
-		return fmt.Errorf("Длина номера паспорта должна ровняться 6! Номер паспорта=%s", user.PassportNumber)
+		return fmt.Errorf("Длина номера паспорта должна ровняться 6!")
 	}
 	if !onlyNumbers(user.PassportNumber) {
-		return fmt.Errorf("Номер паспорта должен содержать только цифры! Номер паспорта=%s", user.PassportNumber)
+		return fmt.Errorf("Номер паспорта должен содержать только цифры!")
 	}
 
 	return nil
 }
 
-func validateFullPassport(user *models.Users) error {
+func validateRole(user *models.Users) error {
+	if !user.Role.Valid() {
+		return fmt.Errorf("Неизвестная роль пользователя: %s", user.Role)
+	}
+	return nil
+}
+
+// Руководителем может быть только существующий пользователь с ролью manager или admin
+func (v *Validator) validateManager(user *models.Users) error {
+	if user.ManagerID == nil {
+		return nil
+	}
+	if *user.ManagerID == user.ID {
+		return fmt.Errorf("Пользователь не может быть руководителем самому себе")
+	}
+
+	var manager models.Users
+	if err := v.DB.Select("id", "role").First(&manager, "id = ?", *user.ManagerID).Error; err != nil {
+		return fmt.Errorf("Руководитель не найден: %v", *user.ManagerID)
+	}
+	if manager.Role != models.RoleManager && manager.Role != models.RoleAdmin {
+		return fmt.Errorf("Руководителем может быть только пользователь с ролью manager или admin")
+	}
+	return nil
+}
+
+// Сам пользователь в проверке не участвует, чтобы обновление не конфликтовало с его же паспортом
+func (v *Validator) validateFullPassport(user *models.Users) error {
 	var count int64
-	err := db.PostgresClient.Model(&models.Users{}).Where("full_passport = ?", user.FullPassport).Count(&count).Error
+	// Удаленные пользователи тоже учитываются: уникальный индекс в БД их не исключает
+	err := v.DB.Unscoped().Model(&models.Users{}).
+		Where("passport_hash = ? AND id <> ?", user.PassportHash, user.ID).
+		Count(&count).Error
 	if err != nil {
 		return fmt.Errorf("Ошибка при выполнении запроса: %v", err)
 	}
 
 	if count > 0 {
-		return fmt.Errorf("Пользователь с таким паспортом уже существует: %s", user.FullPassport)
+		return ErrPassportTaken
 	}
 
 	return nil
@@ -204,6 +358,9 @@ func validateFullPassport(user *models.Users) error {
 
 // Делаем первую букву заглавной
 func normalizedString(str string) string {
+	if str == "" {
+		return str
+	}
 	str = strings.ToLower(str)
 	strRune := []rune(str)
 	strRune[0] = unicode.ToUpper(strRune[0])
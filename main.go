@@ -1,40 +1,83 @@
 package main
 
 import (
-	"github.com/gorilla/mux"
-	httpSwagger "github.com/swaggo/http-swagger"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
 	"net/http"
-	_ "test/docs"
+	"test/internal/app"
+	"test/internal/auth"
+	"test/internal/clock"
 	"test/internal/db"
-	"test/internal/handlers/crud/tasks"
-	"test/internal/handlers/crud/users"
 	"test/internal/logging"
+	"test/internal/peopleinfo"
+	"test/internal/pii"
 )
 
+// @title       Task Management API
+// @version     1.0.0
+// @description API для управления пользователями и задачами
+// @host        localhost:8080
+// @BasePath    /
+// @schemes     http
 func main() {
-	logging.InitLogger()
-	db.ConnectDB()
+	log := logging.NewLogger()
 
-	router := mux.NewRouter()
+	if err := godotenv.Load("./.env"); err != nil {
+		log.WithFields(logrus.Fields{
+			"error": err,
+		}).Fatal("Не удалось загрузить .env файл")
+	}
 
-	logging.Log.Info("Создан роутинг")
+	log.Debug("Загружен .env файл")
 
-	usersRouter := router.PathPrefix("/users").Subrouter()
-	usersRouter.HandleFunc("/create", users.CreateUser).Methods("POST")
-	usersRouter.HandleFunc("/delete/{id}", users.DeleteUserByID).Methods("DELETE")
-	usersRouter.HandleFunc("/update/{id}", users.UpdateUserByID).Methods("PUT")
-	usersRouter.HandleFunc("/get/{id}", users.GetUserByID).Methods("GET")
-	usersRouter.HandleFunc("/list", users.GetUsers).Methods("POST")
-	usersRouter.HandleFunc("/laborCost/{user_id}", users.LaborCost).Methods("POST")
+	// Ключи нужны до миграции: при ней шифруются паспортные данные, записанные открытым текстом
+	cipher, err := pii.LoadCipher()
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": err,
+		}).Fatal("Не удалось загрузить ключи шифрования персональных данных")
+	}
 
-	tasksRouter := router.PathPrefix("/tasks").Subrouter()
-	tasksRouter.HandleFunc("/create/{user_id}", tasks.CreateTask).Methods("POST")
-	tasksRouter.HandleFunc("/start/{id}", tasks.StartTaskTimer).Methods("POST")
-	tasksRouter.HandleFunc("/stop/{id}", tasks.StopTaskTimer).Methods("POST")
+	database, err := db.ConnectDB(log, cipher)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": err,
+		}).Fatal("Не удалось подготовить БД")
+	}
 
-	router.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
+	authConfig, err := auth.LoadConfig()
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": err,
+		}).Fatal("Не удалось настроить аутентификацию")
+	}
 
-	http.ListenAndServe("localhost:8080", router)
+	log.WithFields(logrus.Fields{
+		"ttl":       authConfig.TokenTTL,
+		"bootstrap": authConfig.BootstrapSecret != "",
+	}).Info("Настроена аутентификация")
 
-	logging.Log.Info("Сервис готов. Открыто соединение для прослушивания запросов")
+	peopleInfo, err := peopleinfo.NewClientFromEnv(log)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": err,
+		}).Fatal("Не удалось настроить клиент сервиса получения данных по паспорту")
+	}
+
+	application := &app.App{
+		DB:         database,
+		Log:        log,
+		Clock:      clock.System{},
+		AuthConfig: authConfig,
+		Cipher:     cipher,
+		PeopleInfo: peopleInfo,
+	}
+
+	log.Info("Сервис готов. Открыто соединение для прослушивания запросов")
+
+	if err = http.ListenAndServe("localhost:8080", application.Router()); err != nil {
+		log.WithFields(logrus.Fields{
+			"error": err,
+		}).Fatal("Сервер остановлен")
+	}
 }